@@ -0,0 +1,89 @@
+// Commande router : le routeur sans interface graphique, pour une machine
+// sans affichage. Elle ne dépend pas de Fyne.
+package main
+
+import (
+    "context"
+    "flag"
+    "guitarHetic/internal/config"
+    "guitarHetic/internal/domain/ehub"
+    infra_artnet "guitarHetic/internal/infrastructure/artnet"
+    infra_ehub "guitarHetic/internal/infrastructure/ehub"
+    _ "guitarHetic/internal/infrastructure/sacn" // enregistre le transport sACN
+    "guitarHetic/internal/pipeline"
+    "log"
+    "os"
+    "os/signal"
+    "syscall"
+)
+
+func main() {
+    var opts pipeline.CommandLine
+    var routingPath, patchPath string
+    flag.StringVar(&routingPath, "routing", "", "Fichier de routage (.xlsx ou .csv) à charger (obligatoire)")
+    flag.StringVar(&patchPath, "patch", "", "Fichier de patch (.xlsx) à appliquer au démarrage")
+    opts.RegisterFlags(flag.CommandLine)
+    flag.Parse()
+    opts.LoadSettings()
+
+    runHeadless(opts, routingPath, patchPath)
+}
+
+func runHeadless(opts pipeline.CommandLine, routingPath, patchPath string) {
+    log.Println("Démarrage du système en mode headless...")
+
+    if routingPath == "" {
+        log.Fatal("ERREUR: Le mode headless nécessite un fichier de routage (-routing).")
+    }
+    if opts.FPS <= 0 {
+        log.Fatalf("ERREUR: Cadence invalide: %d FPS", opts.FPS)
+    }
+
+    cfg, report, err := config.Load(routingPath)
+    if err != nil {
+        log.Fatalf("ERREUR: Impossible de charger le fichier de configuration: %v", err)
+    }
+    if report.HasIssues() {
+        log.Printf("ATTENTION: Configuration chargée avec des anomalies: %s", report.Summary())
+    }
+
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+
+    artnetNode := infra_artnet.NewNode()
+    defer artnetNode.Close()
+    artnetNode.SetUniverses(pipeline.ConfiguredUniverses(cfg))
+    if err := artnetNode.Apply(ctx, opts.Settings); err != nil {
+        log.Printf("ERREUR: Le routeur ne répondra pas aux ArtPoll: %v", err)
+    }
+
+    rawPacketChannel := make(chan ehub.RawPacket, 1000)
+    eHubEndpoint := infra_ehub.NewEndpoint(rawPacketChannel)
+    if err := eHubEndpoint.Apply(ctx, opts.Settings.EHub); err != nil {
+        log.Fatalf("ERREUR: Impossible d'écouter eHub: %v", err)
+    }
+    defer eHubEndpoint.Close()
+
+    eHubUpdateChannel := make(chan *ehub.EHubUpdateMsg, 1000)
+    processorService, senderDone, err := pipeline.Start(ctx, cfg, nil, rawPacketChannel, eHubUpdateChannel, nil, nil, nil, opts.Pipeline())
+    if err != nil {
+        log.Fatalf("ERREUR: Impossible de démarrer le pipeline de traitement: %v", err)
+    }
+
+    if patchPath != "" {
+        patchMap, err := config.LoadPatchMapFromExcel(patchPath)
+        if err != nil {
+            log.Printf("ERREUR: Impossible de charger le fichier de patch: %v", err)
+        } else {
+            processorService.SetPatchMap(patchMap)
+            processorService.SetPatchingActive(true)
+        }
+    }
+
+    log.Printf("Système démarré. Écoute eHub sur le port %d, envoi Art-Net à %d FPS.", opts.Settings.EHub.Port, opts.FPS)
+    <-ctx.Done()
+
+    log.Println("Signal d'arrêt reçu, arrêt du pipeline...")
+    <-senderDone
+    log.Println("Arrêt complet de l'application.")
+}
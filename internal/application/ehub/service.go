@@ -1,6 +1,7 @@
 package ehub
 
 import (
+	"context"
 	"guitarHetic/internal/domain/ehub"
 	"log"
 )
@@ -26,11 +27,21 @@ func NewService(
 	}
 }
 
-func (s *Service) Start() {
+// Start s'arrête avec ctx : le canal de paquets bruts est partagé par les
+// pipelines successifs et ne doit plus être lu par un service arrêté.
+func (s *Service) Start(ctx context.Context) {
 	go func() {
 		log.Println("eHub Service: Démarré, prêt à parser et router les messages.")
 		
-		for rawPkt := range s.rawPacketIn {
+		for {
+			var rawPkt ehub.RawPacket
+			select {
+			case <-ctx.Done():
+				log.Println("eHub Service: Arrêt.")
+				return
+			case rawPkt = <-s.rawPacketIn:
+			}
+
 			parsedMessage, err := s.parser.Parse(rawPkt.Data)
 			if err != nil {
 				log.Printf("eHub Service: Erreur de parsing: %v", err)
@@ -38,15 +49,29 @@ func (s *Service) Start() {
 			}
 			
 			
+			source := ""
+			if rawPkt.From != nil {
+				source = rawPkt.From.IP.String()
+			}
+
 			switch msg := parsedMessage.(type) {
 			case *ehub.EHubConfigMsg:
 				// Logs supprimés pour interface propre
-				s.configOut <- msg
+				msg.Source = source
+				select {
+				case s.configOut <- msg:
+				case <-ctx.Done():
+					return
+				}
 				
 			case *ehub.EHubUpdateMsg:
 				// Logs supprimés pour interface propre
-				
-   				s.updateOut <- msg
+				msg.Source = source
+				select {
+				case s.updateOut <- msg:
+				case <-ctx.Done():
+					return
+				}
 			default:
 				log.Printf("eHub Service: Type de message inconnu reçu du parser.")
 			}
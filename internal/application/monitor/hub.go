@@ -0,0 +1,52 @@
+package monitor
+
+import (
+    "guitarHetic/internal/domain/monitor"
+    "log"
+    "sync"
+)
+
+type Hub struct {
+    mu          sync.RWMutex
+    subscribers []chan *monitor.UniverseMonitorData
+}
+
+var _ monitor.Publisher = (*Hub)(nil)
+
+func NewHub() *Hub {
+    return &Hub{}
+}
+
+func (h *Hub) Subscribe(buffer int) <-chan *monitor.UniverseMonitorData {
+    ch := make(chan *monitor.UniverseMonitorData, buffer)
+    h.mu.Lock()
+    h.subscribers = append(h.subscribers, ch)
+    h.mu.Unlock()
+    return ch
+}
+
+func (h *Hub) Unsubscribe(sub <-chan *monitor.UniverseMonitorData) {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    for i, ch := range h.subscribers {
+        if ch == sub {
+            h.subscribers = append(h.subscribers[:i], h.subscribers[i+1:]...)
+            close(ch)
+            return
+        }
+    }
+}
+
+// Publish n'est jamais bloquant : un abonné trop lent perd des paquets
+// plutôt que de ralentir le routage.
+func (h *Hub) Publish(data *monitor.UniverseMonitorData) {
+    h.mu.RLock()
+    defer h.mu.RUnlock()
+    for _, ch := range h.subscribers {
+        select {
+        case ch <- data:
+        default:
+            log.Println("MONITOR_WARN: Un canal de monitoring est plein, un paquet est ignoré.")
+        }
+    }
+}
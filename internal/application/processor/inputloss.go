@@ -0,0 +1,175 @@
+package processor
+
+import (
+    "context"
+    "fmt"
+    "guitarHetic/internal/config"
+    "guitarHetic/internal/domain/monitor"
+    "guitarHetic/internal/domain/output"
+    "log"
+    "strings"
+    "time"
+)
+
+type LossAction int
+
+const (
+    // LossHold garde la dernière trame, renvoyée à chaque tick.
+    LossHold LossAction = iota
+    // LossFade descend progressivement toutes les sorties au noir.
+    LossFade
+    // LossPattern passe sur un motif du Faker.
+    LossPattern
+)
+
+var lossActionNames = map[LossAction]string{
+    LossHold:    "maintien de la dernière trame",
+    LossFade:    "fondu au noir",
+    LossPattern: "motif de secours",
+}
+
+var lossActionAliases = map[string]LossAction{
+    "HOLD":    LossHold,
+    "FADE":    LossFade,
+    "PATTERN": LossPattern,
+}
+
+func ParseLossAction(s string) (LossAction, error) {
+    name := strings.ToUpper(strings.TrimSpace(s))
+    if name == "" {
+        return LossHold, nil
+    }
+    if a, ok := lossActionAliases[name]; ok {
+        return a, nil
+    }
+    return LossHold, fmt.Errorf("action de perte d'entrée inconnue: '%s' (attendu hold, fade ou pattern)", s)
+}
+
+func (a LossAction) String() string {
+    if name, ok := lossActionNames[a]; ok {
+        return name
+    }
+    return fmt.Sprintf("LossAction(%d)", int(a))
+}
+
+// Un Timeout nul désactive la détection de perte d'entrée.
+type InputLoss struct {
+    Timeout time.Duration
+    Action  LossAction
+    Fade    time.Duration
+    Pattern string
+}
+
+func NewInputLoss(settings config.InputLossSettings) (InputLoss, error) {
+    action, err := ParseLossAction(settings.Action)
+    if err != nil {
+        return InputLoss{}, err
+    }
+    if settings.TimeoutMs < 0 {
+        return InputLoss{}, fmt.Errorf("délai de perte d'entrée invalide: %d ms", settings.TimeoutMs)
+    }
+    if action == LossFade && settings.FadeSeconds <= 0 {
+        return InputLoss{}, fmt.Errorf("durée de fondu invalide: %g s", settings.FadeSeconds)
+    }
+    if action == LossPattern && strings.TrimSpace(settings.Pattern) == "" {
+        return InputLoss{}, fmt.Errorf("aucun motif de secours choisi pour l'action pattern")
+    }
+    return InputLoss{
+        Timeout: time.Duration(settings.TimeoutMs) * time.Millisecond,
+        Action:  action,
+        Fade:    time.Duration(settings.FadeSeconds * float64(time.Second)),
+        Pattern: strings.TrimSpace(settings.Pattern),
+    }, nil
+}
+
+const fadeStep = 40 * time.Millisecond
+
+// FadeOut s'arrête à la prochaine mise à jour eHub ou avec ctx ; arrivé au
+// bout, l'état est remis à zéro pour que la reprise parte du noir.
+func (s *Service) FadeOut(ctx context.Context, duration time.Duration) {
+    s.stateMutex.Lock()
+    s.fadeGeneration++
+    s.fading = true
+    generation := s.fadeGeneration
+    s.stateMutex.Unlock()
+
+    log.Printf("Processor: Fondu au noir sur %s.", duration)
+    go func() {
+        start := time.Now()
+        ticker := time.NewTicker(fadeStep)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case <-ticker.C:
+            }
+            level := 1 - float64(time.Since(start))/float64(duration)
+            if !s.fadeStep(ctx, generation, max(level, 0)) || level <= 0 {
+                return
+            }
+        }
+    }()
+}
+
+// fadeStep renvoie false si le fondu a été interrompu.
+func (s *Service) fadeStep(ctx context.Context, generation int, level float64) bool {
+    s.stateMutex.Lock()
+    if generation != s.fadeGeneration || s.lastPhysicalConfig == nil {
+        s.stateMutex.Unlock()
+        return false
+    }
+
+    frames := s.heldFrames(nil)
+    for i := range frames {
+        for ch, value := range frames[i].Data {
+            frames[i].Data[ch] = byte(float64(value) * level)
+        }
+    }
+
+    if level == 0 {
+        s.fading = false
+        for _, state := range s.persistentStates {
+            *state = [512]byte{}
+        }
+        // Un émetteur encore dans son délai de fusion garde son état.
+        now := time.Now()
+        for address, source := range s.sources {
+            if now.Sub(source.lastSeen) > s.merge.Timeout {
+                delete(s.sources, address)
+            }
+        }
+    }
+    s.stateMutex.Unlock()
+
+    return s.sendFrames(ctx, frames)
+}
+
+// heldFrames est appelée sous stateMutex.
+func (s *Service) heldFrames(skip map[output.UniverseKey]struct{}) []output.Frame {
+    var frames []output.Frame
+    for universe, state := range s.persistentStates {
+        if _, done := skip[universe]; done {
+            continue
+        }
+        if _, ok := s.lastPhysicalConfig.UniverseTargets[universe]; !ok {
+            continue
+        }
+        frames = append(frames, output.Frame{Protocol: universe.Protocol, Universe: universe.Universe, Data: s.patched(universe, state)})
+    }
+    return frames
+}
+
+func (s *Service) sendFrames(ctx context.Context, frames []output.Frame) bool {
+    for _, frame := range frames {
+        select {
+        case s.dest <- frame:
+        case <-ctx.Done():
+            return false
+        }
+        if s.monitor != nil {
+            s.monitor.Publish(&monitor.UniverseMonitorData{Universe: frame.Key(), OutputDMX: frame.Data})
+        }
+    }
+    return true
+}
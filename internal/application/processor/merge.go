@@ -0,0 +1,132 @@
+package processor
+
+import (
+    "fmt"
+    "guitarHetic/internal/config"
+    "guitarHetic/internal/domain/output"
+    "net"
+    "strings"
+    "time"
+)
+
+type MergePolicy int
+
+const (
+    // MergeLTP garde la dernière valeur reçue, quel que soit l'émetteur.
+    MergeLTP MergePolicy = iota
+    // MergeHTP garde, canal par canal, la plus haute valeur des émetteurs actifs.
+    MergeHTP
+    // MergePriority donne l'univers entier à l'émetteur actif de plus haute priorité.
+    MergePriority
+)
+
+var mergePolicyNames = map[MergePolicy]string{
+    MergeLTP:      "LTP",
+    MergeHTP:      "HTP",
+    MergePriority: "priorité",
+}
+
+var mergePolicyAliases = map[string]MergePolicy{
+    "LTP":      MergeLTP,
+    "HTP":      MergeHTP,
+    "PRIORITY": MergePriority,
+    "PRIORITÉ": MergePriority,
+    "PRIORITE": MergePriority,
+}
+
+func ParseMergePolicy(s string) (MergePolicy, error) {
+    name := strings.ToUpper(strings.TrimSpace(s))
+    if name == "" {
+        return MergeLTP, nil
+    }
+    if p, ok := mergePolicyAliases[name]; ok {
+        return p, nil
+    }
+    return MergeLTP, fmt.Errorf("politique de fusion eHub inconnue: '%s' (attendu ltp, htp ou priority)", s)
+}
+
+func (p MergePolicy) String() string {
+    if name, ok := mergePolicyNames[p]; ok {
+        return name
+    }
+    return fmt.Sprintf("MergePolicy(%d)", int(p))
+}
+
+type Merge struct {
+    Policy     MergePolicy
+    Timeout    time.Duration
+    Priorities map[string]int
+}
+
+func NewMerge(settings config.MergeSettings) (Merge, error) {
+    policy, err := ParseMergePolicy(settings.Policy)
+    if err != nil {
+        return Merge{}, err
+    }
+    if settings.TimeoutMs <= 0 {
+        return Merge{}, fmt.Errorf("délai de fusion eHub invalide: %d ms", settings.TimeoutMs)
+    }
+    // Les adresses sont normalisées comme celles des paquets reçus.
+    priorities := make(map[string]int, len(settings.Priorities))
+    for address, priority := range settings.Priorities {
+        ip := net.ParseIP(strings.TrimSpace(address))
+        if ip == nil {
+            return Merge{}, fmt.Errorf("adresse d'émetteur eHub invalide dans les priorités: '%s'", address)
+        }
+        priorities[ip.String()] = priority
+    }
+    return Merge{Policy: policy, Timeout: time.Duration(settings.TimeoutMs) * time.Millisecond, Priorities: priorities}, nil
+}
+
+type sourceState struct {
+    lastSeen  time.Time
+    universes map[output.UniverseKey]*[512]byte
+}
+
+func (st *sourceState) universe(u output.UniverseKey) *[512]byte {
+    buffer, ok := st.universes[u]
+    if !ok {
+        buffer = new([512]byte)
+        st.universes[u] = buffer
+    }
+    return buffer
+}
+
+// Les émetteurs muets depuis plus que Timeout sont oubliés.
+func (m Merge) mergeUniverse(u output.UniverseKey, merged *[512]byte, sources map[string]*sourceState, now time.Time) {
+    for address, st := range sources {
+        if now.Sub(st.lastSeen) > m.Timeout {
+            delete(sources, address)
+        }
+    }
+
+    switch m.Policy {
+    case MergeHTP:
+        *merged = [512]byte{}
+        for _, st := range sources {
+            buffer, ok := st.universes[u]
+            if !ok {
+                continue
+            }
+            for ch, value := range buffer {
+                merged[ch] = max(merged[ch], value)
+            }
+        }
+    case MergePriority:
+        // À priorité égale, l'émetteur entendu le plus récemment l'emporte.
+        var winner *sourceState
+        winnerPriority := 0
+        for address, st := range sources {
+            if _, ok := st.universes[u]; !ok {
+                continue
+            }
+            priority := m.Priorities[address]
+            if winner == nil || priority > winnerPriority || (priority == winnerPriority && st.lastSeen.After(winner.lastSeen)) {
+                winner, winnerPriority = st, priority
+            }
+        }
+        if winner != nil {
+            *merged = *winner.universes[u]
+        }
+    }
+}
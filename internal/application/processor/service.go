@@ -1,22 +1,38 @@
 package processor
 
 import (
+    "context"
     "guitarHetic/internal/config"
-    "guitarHetic/internal/domain/artnet"
     "guitarHetic/internal/domain/ehub"
-    "guitarHetic/internal/ui"
+    "guitarHetic/internal/domain/monitor"
+    "guitarHetic/internal/domain/output"
+    "guitarHetic/internal/domain/pixel"
     "log"
     "reflect"
+    "slices"
     "sync"
+    "time"
 )
 
-type DestinationChannel chan<- artnet.LEDMessage
+type DestinationChannel chan<- output.Frame
 
 type FinalRouteInfo struct {
     IsEnabled       bool
     TargetIP        string
-    TargetUniverse  int
+    TargetUniverse  output.UniverseKey
     DMXBufferOffset int
+    Format          pixel.Format
+}
+
+type pixelSlot struct {
+    Offset int
+    Format pixel.Format
+}
+
+// Chaque source et univers eHub a sa configuration et sa table de routage.
+type inputKey struct {
+    Source   string
+    Universe int
 }
 
 type Service struct {
@@ -24,13 +40,18 @@ type Service struct {
     updateMsgIn        <-chan *ehub.EHubUpdateMsg
     PhysicalConfigIn   chan *config.Config
     dest               DestinationChannel
-    routingTable       []FinalRouteInfo
-    lastUsedConfigMsg  *ehub.EHubConfigMsg
+    routingTables      map[inputKey][]FinalRouteInfo
+    configMsgs         map[inputKey]*ehub.EHubConfigMsg
     lastPhysicalConfig *config.Config
-    persistentStates   map[int]*[512]byte
+    persistentStates   map[output.UniverseKey]*[512]byte
+    merge              Merge
+    sources            map[string]*sourceState
+    fadeGeneration     int
+    fading             bool
     stateMutex         sync.Mutex
-    monitorOut         chan<- *ui.UniverseMonitorData
+    monitor            monitor.Publisher
     patchMap           map[int]map[int][]int
+    pixelLayouts       map[output.UniverseKey][]pixelSlot
     isPatchingActive   bool
 }
 
@@ -38,7 +59,8 @@ func NewService(
     configMsgIn <-chan *ehub.EHubConfigMsg,
     updateMsgIn <-chan *ehub.EHubUpdateMsg,
     dest DestinationChannel,
-    monitorOut chan<- *ui.UniverseMonitorData,
+    monitorPublisher monitor.Publisher,
+    merge Merge,
 ) (*Service, chan *config.Config) {
     physicalConfigChan := make(chan *config.Config)
     return &Service{
@@ -46,8 +68,12 @@ func NewService(
         updateMsgIn:      updateMsgIn,
         PhysicalConfigIn: physicalConfigChan,
         dest:             dest,
-        persistentStates: make(map[int]*[512]byte),
-        monitorOut:       monitorOut,
+        routingTables:    make(map[inputKey][]FinalRouteInfo),
+        configMsgs:       make(map[inputKey]*ehub.EHubConfigMsg),
+        persistentStates: make(map[output.UniverseKey]*[512]byte),
+        merge:            merge,
+        sources:          make(map[string]*sourceState),
+        monitor:          monitorPublisher,
         patchMap:         nil,
         isPatchingActive: false,
     }, physicalConfigChan
@@ -71,44 +97,64 @@ func (s *Service) SetPatchingActive(active bool) {
     }
 }
 
-func (s *Service) Start() {
+func (s *Service) Start(ctx context.Context) {
     go func() {
         log.Println("Processor: Service démarré (mode stateful optimisé).")
         for {
             select {
+            case <-ctx.Done():
+                log.Println("Processor: Arrêt.")
+                return
             case newPhysicalConfig := <-s.PhysicalConfigIn:
                 s.handleNewPhysicalConfig(newPhysicalConfig)
             case newConfigMsg := <-s.configMsgIn:
                 s.handleNewEHubConfig(newConfigMsg)
             case updateMsg := <-s.updateMsgIn:
-                s.processUpdate(updateMsg)
+                s.processUpdate(ctx, updateMsg)
             }
         }
     }()
 }
 
-func (s *Service) processUpdate(updateMsg *ehub.EHubUpdateMsg) {
-    if s.routingTable == nil || s.lastPhysicalConfig == nil {
+func (s *Service) processUpdate(ctx context.Context, updateMsg *ehub.EHubUpdateMsg) {
+    routingTable := s.routingTables[inputKey{Source: updateMsg.Source, Universe: updateMsg.Universe}]
+    if routingTable == nil || s.lastPhysicalConfig == nil {
         return
     }
 
     s.stateMutex.Lock()
-    defer s.stateMutex.Unlock()
 
-    modifiedUniverses := make(map[int]struct{})
+    // Une mise à jour reçue interrompt un fondu en cours.
+    s.fadeGeneration++
+    fadeCancelled := s.fading
+    s.fading = false
+
+    modifiedUniverses := make(map[output.UniverseKey]struct{})
+
+    // En LTP, l'émetteur écrit directement dans l'état fusionné.
+    now := time.Now()
+    var source *sourceState
+    if s.merge.Policy != MergeLTP {
+        source = s.sources[updateMsg.Source]
+        if source == nil {
+            source = &sourceState{universes: make(map[output.UniverseKey]*[512]byte)}
+            s.sources[updateMsg.Source] = source
+        }
+        source.lastSeen = now
+    }
 
     for _, entity := range updateMsg.Entities {
         const noiseThreshold = 15
-        if entity.Red < noiseThreshold && entity.Green < noiseThreshold && entity.Blue < noiseThreshold {
-            entity.Red, entity.Green, entity.Blue = 0, 0, 0
+        if entity.Red < noiseThreshold && entity.Green < noiseThreshold && entity.Blue < noiseThreshold && entity.White < noiseThreshold {
+            entity.Red, entity.Green, entity.Blue, entity.White = 0, 0, 0, 0
         }
 
-        entityIndex := int(entity.ID)
-        if entityIndex >= len(s.routingTable) {
+        sextuor := int(entity.ID)
+        if sextuor >= len(routingTable) {
             continue
         }
 
-        routeInfo := s.routingTable[entityIndex]
+        routeInfo := routingTable[sextuor]
         if !routeInfo.IsEnabled {
             continue
         }
@@ -119,134 +165,214 @@ func (s *Service) processUpdate(updateMsg *ehub.EHubUpdateMsg) {
         if _, ok := s.persistentStates[universe]; !ok {
             s.persistentStates[universe] = new([512]byte)
         }
+        buffer := s.persistentStates[universe]
+        if source != nil {
+            buffer = source.universe(universe)
+        }
 
-        if offset+2 < 512 {
-            s.persistentStates[universe][offset+0] = entity.Red
-            s.persistentStates[universe][offset+1] = entity.Green
-            s.persistentStates[universe][offset+2] = entity.Blue
+        channels := routeInfo.Format.Channels()
+        if offset+channels <= 512 {
+            routeInfo.Format.Encode(buffer[offset:offset+channels], entity.Red, entity.Green, entity.Blue, entity.White)
             modifiedUniverses[universe] = struct{}{}
         }
     }
 
+    if source != nil {
+        for universe := range modifiedUniverses {
+            s.merge.mergeUniverse(universe, s.persistentStates[universe], s.sources, now)
+        }
+    }
+
     for universe := range modifiedUniverses {
-        ip, ok := s.lastPhysicalConfig.UniverseIP[universe]
-        if !ok {
+        if _, ok := s.lastPhysicalConfig.UniverseTargets[universe]; !ok {
             continue
         }
         if originalBuffer := s.persistentStates[universe]; originalBuffer != nil {
-            bufferToSend := *originalBuffer
-
-            if s.isPatchingActive {
-                if patchForThisUniverse, ok := s.patchMap[universe]; ok {
-                    patchedBuffer := *originalBuffer
-
-                    for sourceChannel, destinationChannels := range patchForThisUniverse {
-                        sourceIndex := (sourceChannel - 1) * 3
-                        if sourceIndex+2 >= 512 {
-                            continue
-                        }
-
-                        valR := originalBuffer[sourceIndex]
-                        valG := originalBuffer[sourceIndex+1]
-                        valB := originalBuffer[sourceIndex+2]
-
-                        for _, destChannel := range destinationChannels {
-                            destIndex := (destChannel - 1) * 3
-                            if destIndex+2 >= 512 {
-                                continue
-                            }
-                            patchedBuffer[destIndex] = valR
-                            patchedBuffer[destIndex+1] = valG
-                            patchedBuffer[destIndex+2] = valB
-                        }
-
-                        patchedBuffer[sourceIndex] = 0
-                        patchedBuffer[sourceIndex+1] = 0
-                        patchedBuffer[sourceIndex+2] = 0
-                    }
-                    bufferToSend = patchedBuffer
-                }
+            bufferToSend := s.patched(universe, originalBuffer)
+
+            s.dest <- output.Frame{
+                Protocol: universe.Protocol,
+                Universe: universe.Universe,
+                Data:     bufferToSend,
             }
 
-            s.dest <- artnet.LEDMessage{
-                DestinationIP: ip,
-                Universe:      universe,
-                Data:          bufferToSend,
+            if s.monitor == nil {
+                continue
             }
 
             relevantEntities := make([]ehub.EHubEntityState, 0)
             for _, entity := range updateMsg.Entities {
-                entityIndex := int(entity.ID)
-                if entityIndex < len(s.routingTable) {
-                    if s.routingTable[entityIndex].TargetUniverse == universe {
+                sextuor := int(entity.ID)
+                if sextuor < len(routingTable) {
+                    if routingTable[sextuor].TargetUniverse == universe {
                         relevantEntities = append(relevantEntities, entity)
                     }
                 }
             }
 
-            monitorData := &ui.UniverseMonitorData{
-                UniverseID: universe,
+            s.monitor.Publish(&monitor.UniverseMonitorData{
+                Universe:   universe,
                 InputState: relevantEntities,
                 OutputDMX:  bufferToSend,
-            }
+            })
+        }
+    }
 
-            select {
-            case s.monitorOut <- monitorData:
-            default:
-                log.Println("MONITOR_WARN: Le canal de monitoring UI est plein, un paquet est ignoré.")
+    // Les univers non modifiés sont restés au niveau du fondu interrompu.
+    var held []output.Frame
+    if fadeCancelled {
+        held = s.heldFrames(modifiedUniverses)
+    }
+    s.stateMutex.Unlock()
+    s.sendFrames(ctx, held)
+}
+
+// patched applique le patch actif à l'univers ; appelée sous stateMutex.
+// Le patch vaut pour ce numéro d'univers sur chaque protocole.
+func (s *Service) patched(universe output.UniverseKey, originalBuffer *[512]byte) [512]byte {
+    if !s.isPatchingActive {
+        return *originalBuffer
+    }
+    patchForThisUniverse, ok := s.patchMap[universe.Universe]
+    if !ok {
+        return *originalBuffer
+    }
+
+    layout := s.pixelLayouts[universe]
+    patchedBuffer := *originalBuffer
+    // Toutes les sources d'abord : une source peut aussi être destination.
+    for sourceChannel := range patchForThisUniverse {
+        if source, ok := pixelSlotAt(layout, sourceChannel); ok {
+            clear(patchedBuffer[source.Offset : source.Offset+source.Format.Channels()])
+        }
+    }
+    for sourceChannel, destinationChannels := range patchForThisUniverse {
+        source, ok := pixelSlotAt(layout, sourceChannel)
+        if !ok {
+            continue
+        }
+        r, g, b, w := source.Format.Decode(originalBuffer[source.Offset:])
+
+        for _, destChannel := range destinationChannels {
+            destination, ok := pixelSlotAt(layout, destChannel)
+            if !ok {
+                continue
+            }
+            // Un gradateur recopié sur un pixel sans blanc allume les trois couleurs.
+            if source.Format == pixel.Mono && destination.Format != pixel.Mono && !destination.Format.HasWhite() {
+                destination.Format.Encode(patchedBuffer[destination.Offset:], w, w, w, 0)
+                continue
             }
+            destination.Format.Encode(patchedBuffer[destination.Offset:], r, g, b, w)
         }
     }
+    return patchedBuffer
+}
+
+// pixelSlotAt renvoie le n-ième pixel (à partir de 1) ; au-delà des pixels
+// routés, le dernier format se prolonge, RGB pour un univers sans pixel routé.
+func pixelSlotAt(layout []pixelSlot, n int) (pixelSlot, bool) {
+    if n < 1 {
+        return pixelSlot{}, false
+    }
+    slot := pixelSlot{Offset: (n - 1) * pixel.RGB.Channels(), Format: pixel.RGB}
+    if n <= len(layout) {
+        slot = layout[n-1]
+    } else if len(layout) > 0 {
+        last := layout[len(layout)-1]
+        slot = pixelSlot{Offset: last.Offset + (n-len(layout))*last.Format.Channels(), Format: last.Format}
+    }
+    if slot.Offset+slot.Format.Channels() > 512 {
+        return pixelSlot{}, false
+    }
+    return slot, true
+}
+
+func buildPixelLayouts(cfg *config.Config) map[output.UniverseKey][]pixelSlot {
+    layouts := make(map[output.UniverseKey][]pixelSlot)
+    for _, entry := range cfg.RoutingTable {
+        layouts[entry.Key()] = append(layouts[entry.Key()], pixelSlot{Offset: entry.DMXOffset, Format: entry.Format})
+    }
+    // Plusieurs sources eHub peuvent écrire le même pixel : il n'est compté qu'une fois.
+    for key, layout := range layouts {
+        slices.SortFunc(layout, func(a, b pixelSlot) int { return a.Offset - b.Offset })
+        layouts[key] = slices.CompactFunc(layout, func(a, b pixelSlot) bool { return a.Offset == b.Offset })
+    }
+    return layouts
 }
 
 func (s *Service) handleNewPhysicalConfig(cfg *config.Config) {
     log.Println("Processor: Nouvelle configuration physique reçue.")
+    layouts := buildPixelLayouts(cfg)
+    // Le fondu lit la configuration depuis sa propre goroutine.
+    s.stateMutex.Lock()
     s.lastPhysicalConfig = cfg
-    if s.lastUsedConfigMsg != nil {
-        s.buildRoutingTable(s.lastUsedConfigMsg, s.lastPhysicalConfig)
+    s.pixelLayouts = layouts
+    s.stateMutex.Unlock()
+    for key, msg := range s.configMsgs {
+        s.buildRoutingTable(key, msg, s.lastPhysicalConfig)
     }
 }
 
 func (s *Service) handleNewEHubConfig(msg *ehub.EHubConfigMsg) {
-    if s.lastUsedConfigMsg != nil && reflect.DeepEqual(s.lastUsedConfigMsg, msg) {
+    key := inputKey{Source: msg.Source, Universe: msg.Universe}
+    if previous, ok := s.configMsgs[key]; ok && reflect.DeepEqual(previous, msg) {
         return
     }
-    log.Println("Processor: Nouvelle configuration eHuB détectée.")
-    s.lastUsedConfigMsg = msg
+    log.Printf("Processor: Nouvelle configuration eHuB détectée (univers %d, source '%s').", key.Universe, key.Source)
+    s.configMsgs[key] = msg
     if s.lastPhysicalConfig != nil {
-        s.buildRoutingTable(s.lastUsedConfigMsg, s.lastPhysicalConfig)
+        s.buildRoutingTable(key, msg, s.lastPhysicalConfig)
     }
 }
 
-func (s *Service) buildRoutingTable(eHubConfig *ehub.EHubConfigMsg, physicalConfig *config.Config) {
+func (s *Service) buildRoutingTable(key inputKey, eHubConfig *ehub.EHubConfigMsg, physicalConfig *config.Config) {
+    // Une ligne réservée à cette source l'emporte sur une ligne ouverte à tous.
     physicalMap := make(map[int]config.RoutingEntry)
     for _, entry := range physicalConfig.RoutingTable {
+        if !entry.AcceptsEHub(key.Source, key.Universe) {
+            continue
+        }
+        if previous, ok := physicalMap[entry.EntityID]; ok && previous.EHubSource != "" && entry.EHubSource == "" {
+            continue
+        }
         physicalMap[entry.EntityID] = entry
     }
 
-    var maxEntityID uint16 = 0
+    // Indexée par sextuor : les updates ne portent pas l'entité.
+    maxSextuor := -1
     for _, r := range eHubConfig.Ranges {
-        if r.EntityEnd > maxEntityID {
-            maxEntityID = r.EntityEnd
+        if int(r.SextuorEnd)-int(r.SextuorStart) != int(r.EntityEnd)-int(r.EntityStart) {
+            log.Printf("Processor: Plage eHuB incohérente (sextuors %d-%d, entités %d-%d), %d sextuor(s) retenu(s).",
+                r.SextuorStart, r.SextuorEnd, r.EntityStart, r.EntityEnd, r.Len())
+        }
+        if r.Len() > 0 {
+            maxSextuor = max(maxSextuor, int(r.SextuorStart)+r.Len()-1)
         }
     }
 
-    newTable := make([]FinalRouteInfo, maxEntityID+1)
-    log.Printf("Processor: Allocation d'une nouvelle table de routage pour %d entités max.", maxEntityID+1)
+    newTable := make([]FinalRouteInfo, maxSextuor+1)
+    log.Printf("Processor: Allocation d'une nouvelle table de routage pour %d sextuors max.", maxSextuor+1)
 
     for _, eHubRange := range eHubConfig.Ranges {
-        for entityID := eHubRange.EntityStart; entityID <= eHubRange.EntityEnd; entityID++ {
-            if physicalRoute, ok := physicalMap[int(entityID)]; ok {
-                newTable[entityID] = FinalRouteInfo{
+        for i := 0; i < eHubRange.Len(); i++ {
+            sextuor := int(eHubRange.SextuorStart) + i
+            entityID, ok := eHubRange.Entity(sextuor)
+            if !ok {
+                continue
+            }
+            if physicalRoute, ok := physicalMap[entityID]; ok {
+                newTable[sextuor] = FinalRouteInfo{
                     IsEnabled:       true,
                     TargetIP:        physicalRoute.IP,
-                    TargetUniverse:  physicalRoute.Universe,
+                    TargetUniverse:  physicalRoute.Key(),
                     DMXBufferOffset: physicalRoute.DMXOffset,
+                    Format:          physicalRoute.Format,
                 }
             }
         }
     }
 
-    s.routingTable = newTable
-    log.Printf("Processor: Nouvelle table de routage construite et active.")
+    s.routingTables[key] = newTable
+    log.Printf("Processor: Nouvelle table de routage construite et active (univers eHuB %d, source '%s').", key.Universe, key.Source)
 }
@@ -0,0 +1,127 @@
+package processor
+
+import (
+    "context"
+    app_ehub "guitarHetic/internal/application/ehub"
+    "guitarHetic/internal/config"
+    "guitarHetic/internal/domain/artnet"
+    "guitarHetic/internal/domain/ehub"
+    "guitarHetic/internal/domain/output"
+    "guitarHetic/internal/domain/pixel"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+// Entités 100 à 199 en RGB sur l'univers Art-Net 1, l'entité 100 au canal 1.
+// L'entité 0 est routée après elles : un sextuor hors plage ne doit pas
+// l'atteindre.
+const (
+    firstEntity      = 100
+    lastEntity       = 199
+    entityZeroOffset = 300
+)
+
+var testUniverse = output.UniverseKey{Protocol: artnet.Protocol, Universe: 1}
+
+func testPhysicalConfig() *config.Config {
+    cfg := &config.Config{
+        UniverseTargets: map[output.UniverseKey][]output.Target{testUniverse: {{Address: "10.0.0.1"}}},
+        Universes:       map[output.UniverseKey]config.UniverseOutput{testUniverse: {}},
+    }
+    for id := firstEntity; id <= lastEntity; id++ {
+        cfg.RoutingTable = append(cfg.RoutingTable, config.RoutingEntry{
+            Row:       2,
+            EntityID:  id,
+            IP:        "10.0.0.1",
+            Protocol:  artnet.Protocol,
+            Universe:  1,
+            DMXOffset: (id - firstEntity) * 3,
+            Format:    pixel.RGB,
+        })
+    }
+    cfg.RoutingTable = append(cfg.RoutingTable, config.RoutingEntry{
+        Row:       3,
+        EntityID:  0,
+        IP:        "10.0.0.1",
+        Protocol:  artnet.Protocol,
+        Universe:  1,
+        DMXOffset: entityZeroOffset,
+        Format:    pixel.RGB,
+    })
+    return cfg
+}
+
+// readPacket rejoue un paquet eHuB de testdata à travers le parser.
+func readPacket[T any](t *testing.T, parser *app_ehub.Parser, name string) T {
+    t.Helper()
+    packet, err := os.ReadFile(filepath.Join("testdata", name))
+    if err != nil {
+        t.Fatal(err)
+    }
+    msg, err := parser.Parse(packet)
+    if err != nil {
+        t.Fatalf("%s refusé par le parser: %v", name, err)
+    }
+    typed, ok := msg.(T)
+    if !ok {
+        t.Fatalf("%s: message inattendu %T", name, msg)
+    }
+    return typed
+}
+
+func TestProcessUpdateMapsSextuorsToDMXOffsets(t *testing.T) {
+    tests := []struct {
+        // name préfixe les paquets testdata/<name>_config.bin et <name>_update.bin.
+        name string
+        // written associe le canal DMX (à partir de 0) du premier octet d'un
+        // pixel à la couleur attendue ; tous les autres canaux restent à 0.
+        written map[int][3]byte
+    }{
+        // Sextuors 0-9 -> entités 100-109 ; update des sextuors 0 et 9.
+        {"contiguous", map[int][3]byte{0: {200, 100, 50}, 27: {20, 30, 40}}},
+        // Sextuors 0-2 -> 150-152 et 10-11 -> 120-121 ; update des sextuors 1, 5 et 11.
+        {"sparse", map[int][3]byte{(151 - firstEntity) * 3: {90, 80, 70}, (121 - firstEntity) * 3: {60, 50, 40}}},
+        // Sextuors 0-9 -> entités 100-104 ; update des sextuors 4 et 5.
+        {"short_entities", map[int][3]byte{12: {100, 110, 120}}},
+        // Sextuors 20-22 -> entités 130-139 ; update des sextuors 22 et 23.
+        {"short_sextuors", map[int][3]byte{(132 - firstEntity) * 3: {30, 40, 50}}},
+        // Sextuors 10-19 -> entités 100-109 ; update des sextuors 3 et 40.
+        {"outside", nil},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            parser := app_ehub.NewParser()
+            frames := make(chan output.Frame, 10)
+            s, _ := NewService(nil, nil, frames, nil, Merge{Policy: MergeLTP})
+            s.handleNewPhysicalConfig(testPhysicalConfig())
+            s.handleNewEHubConfig(readPacket[*ehub.EHubConfigMsg](t, parser, tt.name+"_config.bin"))
+            s.processUpdate(context.Background(), readPacket[*ehub.EHubUpdateMsg](t, parser, tt.name+"_update.bin"))
+
+            if len(tt.written) == 0 {
+                if len(frames) != 0 {
+                    t.Fatalf("%d trame(s) émise(s), aucune attendue", len(frames))
+                }
+                return
+            }
+            if len(frames) != 1 {
+                t.Fatalf("%d trame(s) émise(s), une attendue", len(frames))
+            }
+            frame := <-frames
+            if frame.Key() != testUniverse {
+                t.Fatalf("trame émise vers l'univers %s, attendu %s", frame.Key(), testUniverse)
+            }
+
+            var expected [512]byte
+            for offset, color := range tt.written {
+                copy(expected[offset:], color[:])
+            }
+            for ch := range expected {
+                if frame.Data[ch] != expected[ch] {
+                    t.Errorf("canal %d: %d, attendu %d", ch+1, frame.Data[ch], expected[ch])
+                }
+            }
+        })
+    }
+}
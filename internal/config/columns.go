@@ -0,0 +1,138 @@
+package config
+
+import (
+    "fmt"
+    "strings"
+)
+
+type column struct {
+    Label    string
+    Aliases  []string
+    Required bool
+}
+
+const (
+    colName         = "Name"
+    colEntityStart  = "Entity Start"
+    colEntityEnd    = "Entity End"
+    colIP           = "ArtNet IP"
+    colUniverse     = "ArtNet Universe"
+    colDMXStart     = "DMX Start"
+    colFormat       = "Pixel Format"
+    colSpill        = "Spill"
+    colPhysical     = "Physical"
+    colFrameLength  = "DMX Length"
+    colProtocol     = "Protocol"
+    colEHubUniverse = "eHub Universe"
+    colEHubSource   = "eHub Source"
+
+    colPatchUniverse    = "Universe"
+    colPatchSource      = "SourceChannel"
+    colPatchDestination = "DestinationChannel"
+)
+
+var routingColumns = []column{
+    {Label: colName, Aliases: []string{"name", "nom"}},
+    {Label: colEntityStart, Aliases: []string{"entity start", "start", "début", "debut", "entité début"}, Required: true},
+    {Label: colEntityEnd, Aliases: []string{"entity end", "end", "fin", "entité fin"}, Required: true},
+    {Label: colIP, Aliases: []string{"artnet ip", "ip", "controller", "contrôleur", "controleur"}, Required: true},
+    {Label: colUniverse, Aliases: []string{"artnet universe", "universe", "univers"}, Required: true},
+    {Label: colDMXStart, Aliases: []string{"dmx start", "dmx address", "start channel", "adresse dmx", "canal de départ", "canal départ"}},
+    {Label: colFormat, Aliases: []string{"pixel format", "format", "led type", "type led", "ordre couleurs"}},
+    {Label: colSpill, Aliases: []string{"spill", "overflow", "débordement", "debordement", "univers suivant"}},
+    {Label: colPhysical, Aliases: []string{"physical", "physical port", "port physique"}},
+    {Label: colFrameLength, Aliases: []string{"dmx length", "frame length", "longueur dmx", "longueur trame"}},
+    {Label: colProtocol, Aliases: []string{"protocol", "protocole", "transport", "output", "sortie"}},
+    {Label: colEHubUniverse, Aliases: []string{"ehub universe", "univers ehub", "ehub"}},
+    {Label: colEHubSource, Aliases: []string{"ehub source", "source ehub", "émetteur ehub", "emetteur ehub"}},
+}
+
+var patchColumns = []column{
+    {Label: colPatchUniverse, Aliases: []string{"universe", "univers", "artnet universe"}, Required: true},
+    {Label: colPatchSource, Aliases: []string{"sourcechannel", "source channel", "source", "canal source"}, Required: true},
+    {Label: colPatchDestination, Aliases: []string{"destinationchannel", "destination channel", "destination", "canal destination"}, Required: true},
+}
+
+type columnIndex map[string]int
+
+func normalizeHeader(h string) string {
+    h = strings.ToLower(h)
+    h = strings.NewReplacer("_", " ", "-", " ", ".", " ").Replace(h)
+    return strings.Join(strings.Fields(h), " ")
+}
+
+func isEmptyRow(row []string) bool {
+    for _, cell := range row {
+        if strings.TrimSpace(cell) != "" {
+            return false
+        }
+    }
+    return true
+}
+
+func findHeaderRow(rows [][]string) int {
+    for i, row := range rows {
+        if !isEmptyRow(row) {
+            return i
+        }
+    }
+    return -1
+}
+
+func resolveColumns(header []string, columns []column) (columnIndex, error) {
+    index := make(columnIndex)
+    for i, cell := range header {
+        name := normalizeHeader(cell)
+        if name == "" {
+            continue
+        }
+        for _, col := range columns {
+            if _, found := index[col.Label]; found {
+                continue
+            }
+            for _, alias := range col.Aliases {
+                if name == alias {
+                    index[col.Label] = i
+                    break
+                }
+            }
+        }
+    }
+
+    var missing []string
+    for _, col := range columns {
+        if _, found := index[col.Label]; !found && col.Required {
+            missing = append(missing, fmt.Sprintf("'%s'", col.Label))
+        }
+    }
+    if len(missing) > 0 {
+        return nil, fmt.Errorf("colonne(s) obligatoire(s) manquante(s): %s (en-tête lu: %s)", strings.Join(missing, ", "), strings.Join(header, " | "))
+    }
+    return index, nil
+}
+
+func (c columnIndex) value(row []string, label string) string {
+    i, ok := c[label]
+    if !ok || i >= len(row) {
+        return ""
+    }
+    return strings.TrimSpace(row[i])
+}
+
+func parseBool(v string) (bool, bool) {
+    switch strings.ToLower(strings.TrimSpace(v)) {
+    case "", "non", "no", "n", "false", "faux", "0":
+        return false, true
+    case "oui", "yes", "o", "y", "true", "vrai", "1", "x":
+        return true, true
+    default:
+        return false, false
+    }
+}
+
+func formatBool(b bool) string {
+    if b {
+        return "oui"
+    }
+    return "non"
+}
@@ -0,0 +1,255 @@
+package config
+
+import (
+    "fmt"
+    "guitarHetic/internal/domain/output"
+    "slices"
+    "sort"
+    "strings"
+)
+
+type ConflictKind int
+
+const (
+    ConflictDuplicateEntity ConflictKind = iota
+    ConflictChannelOverlap
+    ConflictUniverseIP
+)
+
+func (k ConflictKind) String() string {
+    switch k {
+    case ConflictDuplicateEntity:
+        return "entité en double"
+    case ConflictChannelOverlap:
+        return "chevauchement DMX"
+    case ConflictUniverseIP:
+        return "univers multi-IP"
+    default:
+        return "conflit"
+    }
+}
+
+type Conflict struct {
+    Kind        ConflictKind
+    Universe    output.UniverseKey
+    Rows        []int
+    Description string
+}
+
+func (c Conflict) Row() int {
+    if len(c.Rows) == 0 {
+        return 0
+    }
+    return c.Rows[len(c.Rows)-1]
+}
+
+func (c Conflict) Column() string {
+    switch c.Kind {
+    case ConflictDuplicateEntity:
+        return colEntityStart
+    case ConflictUniverseIP:
+        return colIP
+    default:
+        return colUniverse
+    }
+}
+
+// Une alerte par paire de lignes, pas par entité.
+type rowPair struct {
+    Universe output.UniverseKey
+    First    int
+    Second   int
+}
+
+type span struct {
+    Min   int
+    Max   int
+    Count int
+}
+
+func (s *span) extend(v int) {
+    if s.Count == 0 || v < s.Min {
+        s.Min = v
+    }
+    if s.Count == 0 || v > s.Max {
+        s.Max = v
+    }
+    s.Count++
+}
+
+// mirrors donne, par univers, les adresses déclarées comme copies.
+func AnalyzeConflicts(cfg *Config, mirrors map[output.UniverseKey]map[string]bool) []Conflict {
+    if cfg == nil {
+        return nil
+    }
+
+    var conflicts []Conflict
+    conflicts = append(conflicts, findDuplicateEntities(cfg.RoutingTable)...)
+    conflicts = append(conflicts, findChannelOverlaps(cfg.RoutingTable)...)
+    conflicts = append(conflicts, findUniverseIPConflicts(cfg.RoutingTable, mirrors)...)
+    return conflicts
+}
+
+// Une même entité peut être routée une fois par univers eHub et par source.
+func findDuplicateEntities(table []RoutingEntry) []Conflict {
+    type entityKey struct {
+        EHubSource   string
+        EHubUniverse int
+        EntityID     int
+    }
+    firstRow := make(map[entityKey]int)
+    spans := make(map[rowPair]*span)
+    for _, entry := range table {
+        entity := entityKey{EHubSource: entry.EHubSource, EHubUniverse: entry.EHubUniverse, EntityID: entry.EntityID}
+        row, seen := firstRow[entity]
+        if !seen {
+            firstRow[entity] = entry.Row
+            continue
+        }
+        key := rowPair{First: row, Second: entry.Row}
+        if spans[key] == nil {
+            spans[key] = &span{}
+        }
+        spans[key].extend(entry.EntityID)
+    }
+
+    var conflicts []Conflict
+    for _, key := range sortedPairs(spans) {
+        sp := spans[key]
+        conflicts = append(conflicts, Conflict{
+            Kind: ConflictDuplicateEntity,
+            Rows: []int{key.First, key.Second},
+            Description: fmt.Sprintf("%d entité(s) (%s) déjà routée(s) par la ligne %d, la dernière définition l'emporte",
+                sp.Count, formatSpan(sp), key.First),
+        })
+    }
+    return conflicts
+}
+
+// Des entrées eHub différentes partagent des canaux à dessein, via la fusion.
+func findChannelOverlaps(table []RoutingEntry) []Conflict {
+    type channelKey struct {
+        Universe     output.UniverseKey
+        Channel      int
+        EHubUniverse int
+        EHubSource   string
+    }
+    owner := make(map[channelKey]RoutingEntry)
+    spans := make(map[rowPair]*span)
+    for _, entry := range table {
+        for ch := entry.DMXOffset; ch < entry.DMXOffset+entry.Format.Channels(); ch++ {
+            key := channelKey{Universe: entry.Key(), Channel: ch, EHubUniverse: entry.EHubUniverse, EHubSource: entry.EHubSource}
+            previous, used := owner[key]
+            if !used {
+                owner[key] = entry
+                continue
+            }
+            if previous.EntityID == entry.EntityID && previous.Row == entry.Row {
+                continue
+            }
+            pair := rowPair{Universe: entry.Key(), First: previous.Row, Second: entry.Row}
+            if spans[pair] == nil {
+                spans[pair] = &span{}
+            }
+            spans[pair].extend(ch + 1)
+        }
+    }
+
+    var conflicts []Conflict
+    for _, key := range sortedPairs(spans) {
+        sp := spans[key]
+        description := fmt.Sprintf("univers %s: canaux DMX %s écrits aussi par la ligne %d", key.Universe, formatSpan(sp), key.First)
+        if key.First == key.Second {
+            description = fmt.Sprintf("univers %s: canaux DMX %s écrits par plusieurs entités de la même ligne", key.Universe, formatSpan(sp))
+        }
+        conflicts = append(conflicts, Conflict{
+            Kind:        ConflictChannelOverlap,
+            Universe:    key.Universe,
+            Rows:        []int{key.First, key.Second},
+            Description: description,
+        })
+    }
+    return conflicts
+}
+
+func findUniverseIPConflicts(table []RoutingEntry, mirrors map[output.UniverseKey]map[string]bool) []Conflict {
+    rowsByIP := make(map[output.UniverseKey]map[string][]int)
+    for _, entry := range table {
+        key := entry.Key()
+        if mirrors[key][entry.IP] {
+            continue
+        }
+        if rowsByIP[key] == nil {
+            rowsByIP[key] = make(map[string][]int)
+        }
+        rows := rowsByIP[key][entry.IP]
+        if len(rows) == 0 || rows[len(rows)-1] != entry.Row {
+            rowsByIP[key][entry.IP] = append(rows, entry.Row)
+        }
+    }
+
+    universes := make([]output.UniverseKey, 0, len(rowsByIP))
+    for u, ips := range rowsByIP {
+        if len(ips) > 1 {
+            universes = append(universes, u)
+        }
+    }
+    slices.SortFunc(universes, output.CompareUniverseKeys)
+
+    var conflicts []Conflict
+    for _, u := range universes {
+        ips := make([]string, 0, len(rowsByIP[u]))
+        for ip := range rowsByIP[u] {
+            ips = append(ips, ip)
+        }
+        sort.Strings(ips)
+
+        var parts []string
+        var allRows []int
+        for _, ip := range ips {
+            rows := rowsByIP[u][ip]
+            parts = append(parts, fmt.Sprintf("%s (ligne(s) %s)", ip, joinInts(rows)))
+            allRows = append(allRows, rows...)
+        }
+        sort.Ints(allRows)
+        conflicts = append(conflicts, Conflict{
+            Kind:        ConflictUniverseIP,
+            Universe:    u,
+            Rows:        allRows,
+            Description: fmt.Sprintf("univers %s envoyé vers plusieurs IP: %s", u, strings.Join(parts, ", ")),
+        })
+    }
+    return conflicts
+}
+
+func sortedPairs(spans map[rowPair]*span) []rowPair {
+    keys := make([]rowPair, 0, len(spans))
+    for key := range spans {
+        keys = append(keys, key)
+    }
+    sort.Slice(keys, func(i, j int) bool {
+        if keys[i].Universe != keys[j].Universe {
+            return output.CompareUniverseKeys(keys[i].Universe, keys[j].Universe) < 0
+        }
+        if keys[i].First != keys[j].First {
+            return keys[i].First < keys[j].First
+        }
+        return keys[i].Second < keys[j].Second
+    })
+    return keys
+}
+
+func formatSpan(s *span) string {
+    if s.Min == s.Max {
+        return fmt.Sprintf("%d", s.Min)
+    }
+    return fmt.Sprintf("%d à %d", s.Min, s.Max)
+}
+
+func joinInts(values []int) string {
+    parts := make([]string, len(values))
+    for i, v := range values {
+        parts[i] = fmt.Sprintf("%d", v)
+    }
+    return strings.Join(parts, ", ")
+}
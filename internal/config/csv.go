@@ -0,0 +1,98 @@
+package config
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/csv"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+type fileFormat int
+
+const (
+    formatExcel fileFormat = iota
+    formatCSV
+)
+
+var zipSignature = []byte("PK\x03\x04")
+var utf8BOM = []byte("\xEF\xBB\xBF")
+
+func detectFileFormat(path string) (fileFormat, error) {
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".csv", ".txt":
+        return formatCSV, nil
+    case ".xlsx", ".xlsm":
+        return formatExcel, nil
+    }
+
+    // Extension inconnue : un classeur .xlsx est une archive zip.
+    f, err := os.Open(path)
+    if err != nil {
+        return formatExcel, err
+    }
+    defer f.Close()
+
+    head := make([]byte, len(zipSignature))
+    n, err := io.ReadFull(f, head)
+    if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+        return formatExcel, err
+    }
+    if bytes.Equal(head[:n], zipSignature) {
+        return formatExcel, nil
+    }
+    return formatCSV, nil
+}
+
+func isCSVPath(path string) bool {
+    return strings.ToLower(filepath.Ext(path)) == ".csv"
+}
+
+func readCSVRows(path string) ([][]string, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    data = bytes.TrimPrefix(data, utf8BOM)
+
+    reader := csv.NewReader(bytes.NewReader(data))
+    reader.Comma = sniffCSVDelimiter(data)
+    reader.FieldsPerRecord = -1
+    reader.LazyQuotes = true
+
+    rows, err := reader.ReadAll()
+    if err != nil {
+        return nil, fmt.Errorf("fichier CSV invalide: %w", err)
+    }
+    return rows, nil
+}
+
+// Les exports CSV d'Excel en français utilisent ';', les autres ',' ou une tabulation.
+func sniffCSVDelimiter(data []byte) rune {
+    firstLine, _, _ := bufio.NewReader(bytes.NewReader(data)).ReadLine()
+    best, bestCount := ';', 0
+    for _, candidate := range []rune{';', ',', '\t'} {
+        if count := strings.Count(string(firstLine), string(candidate)); count > bestCount {
+            best, bestCount = candidate, count
+        }
+    }
+    return best
+}
+
+func writeCSVRows(path string, rows [][]string) error {
+    f, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+
+    writer := csv.NewWriter(f)
+    writer.Comma = ';'
+    if err := writer.WriteAll(rows); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
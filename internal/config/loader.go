@@ -3,61 +3,227 @@ package config
 import (
     "fmt"
     "github.com/xuri/excelize/v2"
+    "guitarHetic/internal/domain/artnet"
+    "guitarHetic/internal/domain/output"
+    "guitarHetic/internal/domain/pixel"
     "log"
+    "net"
+    "slices"
     "strconv"
-    "strings"
 )
 
+// MulticastIP envoie un univers sACN à son groupe multicast 239.255.x.y.
+const MulticastIP = "multicast"
+
 type RawEntry struct {
-    Name     string
-    Start    int
-    End      int
-    IP       string
-    Universe int
+    Row          int
+    Name         string
+    Start        int
+    End          int
+    IP           string
+    Targets      []output.Target
+    Universe     int
+    DMXStart     int
+    Format       pixel.Format
+    Spill        bool
+    Physical     int
+    FrameLength  int
+    Protocol     output.Protocol
+    EHubUniverse int
+    EHubSource   string
 }
 
 type RoutingEntry struct {
-    Name      string
-    EntityID  int
-    IP        string
-    Universe  int
-    DMXOffset int
+    Row          int
+    Name         string
+    EntityID     int
+    IP           string
+    Protocol     output.Protocol
+    Universe     int
+    DMXOffset    int
+    Format       pixel.Format
+    EHubUniverse int
+    EHubSource   string
+}
+
+// Une entrée sans source accepte tous les émetteurs ; un message sans source
+// (Faker) atteint toutes les entrées.
+func (e RoutingEntry) AcceptsEHub(source string, universe int) bool {
+    if e.EHubUniverse != universe {
+        return false
+    }
+    return e.EHubSource == "" || source == "" || e.EHubSource == source
+}
+
+func (e RoutingEntry) Key() output.UniverseKey {
+    return output.UniverseKey{Protocol: e.Protocol, Universe: e.Universe}
 }
 
+type UniverseOutput struct {
+    Physical     int
+    FrameLength  int
+    UsedChannels int
+}
+
+// UniverseTargets liste, pour chaque univers, le contrôleur suivi de ses miroirs.
 type Config struct {
-    UniverseIP   map[int]string
-    RoutingTable []RoutingEntry
+    UniverseTargets map[output.UniverseKey][]output.Target
+    Universes       map[output.UniverseKey]UniverseOutput
+    RoutingTable    []RoutingEntry
+}
+
+// FrameLength : la longueur forcée dans le fichier, sinon la plus courte utile.
+func (c *Config) FrameLength(key output.UniverseKey) int {
+    output := c.Universes[key]
+    if output.FrameLength > 0 {
+        return output.FrameLength
+    }
+    return artnet.FrameLength(output.UsedChannels)
 }
 
-func Load(path string) (*Config, error) {
-    raws, err := loadRawEntriesFromExcel(path)
+func (c *Config) UniversesFor(p output.Protocol) []int {
+    var universes []int
+    for _, key := range sortedUniverses(c.Universes) {
+        if key.Protocol == p {
+            universes = append(universes, key.Universe)
+        }
+    }
+    return universes
+}
+
+func (c *Config) Destinations() map[output.Protocol][]output.Destination {
+    destinations := make(map[output.Protocol][]output.Destination)
+    for _, key := range sortedUniverses(c.Universes) {
+        out := c.Universes[key]
+        destinations[key.Protocol] = append(destinations[key.Protocol], output.Destination{
+            Universe:    key.Universe,
+            Targets:     c.UniverseTargets[key],
+            Physical:    out.Physical,
+            FrameLength: c.FrameLength(key),
+        })
+    }
+    return destinations
+}
+
+func Load(path string) (*Config, *Report, error) {
+    report := newReport(path)
+    raws, err := loadRawEntries(path, report)
     if err != nil {
-        return nil, fmt.Errorf("impossible de charger les entrées depuis le fichier Excel: %w", err)
+        report.add(0, "", SeverityError, "chargement impossible: %v", err)
+        return nil, report, fmt.Errorf("impossible de charger les entrées depuis le fichier '%s': %w", path, err)
     }
 
-    universeIP := make(map[int]string)
+    universeTargets := make(map[output.UniverseKey][]output.Target)
+    universes := make(map[output.UniverseKey]UniverseOutput)
+    mirrors := make(map[output.UniverseKey]map[string]bool)
     table := make([]RoutingEntry, 0)
 
     for _, e := range raws {
-        if e.Start == e.End {
-            table = append(table, RoutingEntry{Name: e.Name, EntityID: e.Start, IP: e.IP, Universe: e.Universe, DMXOffset: 0})
-            universeIP[e.Universe] = e.IP
-            continue
+        for _, entry := range expandRawEntry(e, report) {
+            table = append(table, entry)
+            key := entry.Key()
+            universeTargets[key] = mergeTargets(universeTargets[key], e.Targets)
+            for i, mirror := range e.Targets {
+                if i == 0 {
+                    continue
+                }
+                if mirrors[key] == nil {
+                    mirrors[key] = make(map[string]bool)
+                }
+                mirrors[key][mirror.Address] = true
+            }
+
+            out, known := universes[key]
+            if known && out.Physical != e.Physical {
+                report.add(e.Row, colPhysical, SeverityWarning, "univers %s: port physique %d remplacé par %d", key, out.Physical, e.Physical)
+            }
+            out.Physical = e.Physical
+            if e.FrameLength > 0 {
+                if out.FrameLength > 0 && out.FrameLength != e.FrameLength {
+                    report.add(e.Row, colFrameLength, SeverityWarning, "univers %s: longueur de trame %d remplacée par %d", key, out.FrameLength, e.FrameLength)
+                }
+                out.FrameLength = e.FrameLength
+            }
+            out.UsedChannels = max(out.UsedChannels, entry.DMXOffset+entry.Format.Channels())
+            universes[key] = out
         }
+    }
+
+    for _, key := range sortedUniverses(universes) {
+        out := universes[key]
+        if out.FrameLength > 0 && out.FrameLength < out.UsedChannels {
+            report.add(0, colFrameLength, SeverityWarning, "univers %s: la longueur de trame %d coupe des canaux utilisés (jusqu'au canal %d)", key, out.FrameLength, out.UsedChannels)
+        }
+    }
+
+    cfg := &Config{UniverseTargets: universeTargets, Universes: universes, RoutingTable: table}
+    for _, conflict := range AnalyzeConflicts(cfg, mirrors) {
+        report.add(conflict.Row(), conflict.Column(), SeverityWarning, "%s", conflict.Description)
+    }
 
-        for id := e.Start; id <= e.End; id++ {
-            offset := (id - e.Start) * 3
-            if offset < 512 {
-                table = append(table, RoutingEntry{Name: e.Name, EntityID: id, IP: e.IP, Universe: e.Universe, DMXOffset: offset})
-                universeIP[e.Universe] = e.IP
+    if report.HasIssues() {
+        log.Printf("Config Loader: %s", report.Summary())
+    }
+    return cfg, report, nil
+}
+
+// Avec Spill, un pixel qui ne tient plus passe entier à l'univers suivant.
+func expandRawEntry(e RawEntry, report *Report) []RoutingEntry {
+    channels := e.Format.Channels()
+    universe := e.Universe
+    offset := e.DMXStart - 1
+    spec, _ := output.LookupProtocol(e.Protocol)
+
+    var entries []RoutingEntry
+    droppedFirst, dropped := 0, 0
+    for id := e.Start; id <= e.End; id++ {
+        if offset+channels > 512 {
+            if !e.Spill {
+                if dropped == 0 {
+                    droppedFirst = id
+                }
+                dropped++
+                continue
+            }
+            if universe == spec.LastUniverse {
+                report.add(e.Row, colSpill, SeverityWarning, "entités %d à %d ignorées, l'univers %d est le dernier univers %s", id, e.End, universe, e.Protocol)
+                break
             }
+            universe++
+            offset = 0
         }
+        entries = append(entries, RoutingEntry{Row: e.Row, Name: e.Name, EntityID: id, IP: e.IP, Protocol: e.Protocol, Universe: universe, DMXOffset: offset, Format: e.Format, EHubUniverse: e.EHubUniverse, EHubSource: e.EHubSource})
+        offset += channels
     }
 
-    return &Config{UniverseIP: universeIP, RoutingTable: table}, nil
+    if dropped > 0 {
+        report.add(e.Row, colSpill, SeverityWarning, "%d entité(s) (%d à %d) dépassent le canal 512 de l'univers %d et sont ignorées, activez Spill pour les envoyer sur l'univers suivant",
+            dropped, droppedFirst, e.End, e.Universe)
+    }
+    return entries
+}
+
+func sortedUniverses(universes map[output.UniverseKey]UniverseOutput) []output.UniverseKey {
+    keys := make([]output.UniverseKey, 0, len(universes))
+    for key := range universes {
+        keys = append(keys, key)
+    }
+    slices.SortFunc(keys, output.CompareUniverseKeys)
+    return keys
 }
 
-func loadRawEntriesFromExcel(path string) ([]RawEntry, error) {
+func loadRawEntries(path string, report *Report) ([]RawEntry, error) {
+    format, err := detectFileFormat(path)
+    if err != nil {
+        return nil, err
+    }
+    if format == formatCSV {
+        return loadRawEntriesFromCSV(path, report)
+    }
+    return loadRawEntriesFromExcel(path, report)
+}
+
+func loadRawEntriesFromExcel(path string, report *Report) ([]RawEntry, error) {
     f, err := excelize.OpenFile(path)
     if err != nil {
         return nil, err
@@ -74,44 +240,146 @@ func loadRawEntriesFromExcel(path string) ([]RawEntry, error) {
         return nil, err
     }
 
-    var raws []RawEntry
-    for i, row := range rows {
-        if i == 0 {
-            continue
-        }
+    return parseRawEntries(rows, report)
+}
+
+func loadRawEntriesFromCSV(path string, report *Report) ([]RawEntry, error) {
+    rows, err := readCSVRows(path)
+    if err != nil {
+        return nil, err
+    }
+    return parseRawEntries(rows, report)
+}
 
-        if len(row) < 5 {
-            log.Printf("Config Loader: Ligne %d ignorée (pas assez de colonnes)", i+1)
+func parseRawEntries(rows [][]string, report *Report) ([]RawEntry, error) {
+    headerRow := findHeaderRow(rows)
+    if headerRow < 0 {
+        return nil, fmt.Errorf("le fichier de routage est vide")
+    }
+    columns, err := resolveColumns(rows[headerRow], routingColumns)
+    if err != nil {
+        return nil, err
+    }
+
+    var raws []RawEntry
+    for i := headerRow + 1; i < len(rows); i++ {
+        row := rows[i]
+        if isEmptyRow(row) {
             continue
         }
+        report.RowsRead++
 
-        name := strings.TrimSpace(row[0])
+        name := columns.value(row, colName)
 
-        start, err := strconv.Atoi(strings.TrimSpace(row[1]))
+        startStr := columns.value(row, colEntityStart)
+        start, err := strconv.Atoi(startStr)
         if err != nil {
-            log.Printf("Config Loader: Ligne %d ignorée (Entity Start invalide: '%s')", i+1, row[1])
+            report.skip(i+1, colEntityStart, "ligne ignorée, Entity Start invalide: '%s'", startStr)
             continue
         }
 
-        end, err := strconv.Atoi(strings.TrimSpace(row[2]))
+        endStr := columns.value(row, colEntityEnd)
+        end, err := strconv.Atoi(endStr)
         if err != nil {
-            log.Printf("Config Loader: Ligne %d ignorée (Entity End invalide: '%s')", i+1, row[2])
+            report.skip(i+1, colEntityEnd, "ligne ignorée, Entity End invalide: '%s'", endStr)
             continue
         }
 
-        ip := strings.TrimSpace(row[3])
+        ip := columns.value(row, colIP)
         if ip == "" {
-            log.Printf("Config Loader: Ligne %d ignorée (IP manquante)", i+1)
+            report.skip(i+1, colIP, "ligne ignorée, IP manquante")
             continue
         }
 
-        uni, err := strconv.Atoi(strings.TrimSpace(row[4]))
+        // Le protocole décide de la lecture de l'univers, Art-Net par défaut.
+        protocolStr := columns.value(row, colProtocol)
+        if protocolStr == "" {
+            protocolStr = string(artnet.Protocol)
+        }
+        protocol, err := output.ParseProtocol(protocolStr)
+        if err != nil {
+            report.skip(i+1, colProtocol, "ligne ignorée, %v", err)
+            continue
+        }
+        spec, _ := output.LookupProtocol(protocol)
+        uni, err := spec.ParseUniverse(columns.value(row, colUniverse))
         if err != nil {
-            log.Printf("Config Loader: Ligne %d ignorée (ArtNet Universe invalide: '%s')", i+1, row[4])
+            report.skip(i+1, colUniverse, "ligne ignorée, %v", err)
+            continue
+        }
+        targets, err := ParseTargets(ip)
+        if err != nil {
+            report.skip(i+1, colIP, "ligne ignorée, %v", err)
+            continue
+        }
+        if !spec.Multicast && slices.ContainsFunc(targets, func(t output.Target) bool { return t.Address == MulticastIP }) {
+            report.skip(i+1, colIP, "ligne ignorée, l'envoi multicast n'est pas possible en %s", protocol)
             continue
         }
+        ip = targets[0].Address
+
+        dmxStart := 1
+        if dmxStartStr := columns.value(row, colDMXStart); dmxStartStr != "" {
+            dmxStart, err = strconv.Atoi(dmxStartStr)
+            if err != nil || dmxStart < 1 || dmxStart > 512 {
+                report.skip(i+1, colDMXStart, "ligne ignorée, DMX Start invalide: '%s' (attendu 1 à 512)", dmxStartStr)
+                continue
+            }
+        }
+
+        formatStr := columns.value(row, colFormat)
+        format, err := pixel.ParseFormat(formatStr)
+        if err != nil {
+            report.skip(i+1, colFormat, "ligne ignorée, %v", err)
+            continue
+        }
+
+        spillStr := columns.value(row, colSpill)
+        spill, ok := parseBool(spillStr)
+        if !ok {
+            report.skip(i+1, colSpill, "ligne ignorée, valeur Spill invalide: '%s' (attendu oui/non)", spillStr)
+            continue
+        }
+
+        physical := 0
+        if physicalStr := columns.value(row, colPhysical); physicalStr != "" {
+            physical, err = strconv.Atoi(physicalStr)
+            if err != nil || physical < 0 || physical > 255 {
+                report.skip(i+1, colPhysical, "ligne ignorée, port physique invalide: '%s' (attendu 0 à 255)", physicalStr)
+                continue
+            }
+        }
+
+        frameLength := 0
+        if frameLengthStr := columns.value(row, colFrameLength); frameLengthStr != "" {
+            frameLength, err = strconv.Atoi(frameLengthStr)
+            if err != nil || frameLength < 2 || frameLength > 512 {
+                report.skip(i+1, colFrameLength, "ligne ignorée, longueur de trame invalide: '%s' (attendu 2 à 512)", frameLengthStr)
+                continue
+            }
+            frameLength = artnet.FrameLength(frameLength)
+        }
+
+        eHubUniverse := 0
+        if eHubUniverseStr := columns.value(row, colEHubUniverse); eHubUniverseStr != "" {
+            eHubUniverse, err = strconv.Atoi(eHubUniverseStr)
+            if err != nil || eHubUniverse < 0 || eHubUniverse > 255 {
+                report.skip(i+1, colEHubUniverse, "ligne ignorée, univers eHub invalide: '%s' (attendu 0 à 255)", eHubUniverseStr)
+                continue
+            }
+        }
+
+        eHubSource := columns.value(row, colEHubSource)
+        if eHubSource != "" {
+            sourceIP := net.ParseIP(eHubSource)
+            if sourceIP == nil {
+                report.skip(i+1, colEHubSource, "ligne ignorée, source eHub invalide: '%s' (adresse IP attendue)", eHubSource)
+                continue
+            }
+            eHubSource = sourceIP.String()
+        }
 
-        raws = append(raws, RawEntry{Name: name, Start: start, End: end, IP: ip, Universe: uni})
+        raws = append(raws, RawEntry{Row: i + 1, Name: name, Start: start, End: end, IP: ip, Targets: targets, Universe: uni, DMXStart: dmxStart, Format: format, Spill: spill, Physical: physical, FrameLength: frameLength, Protocol: protocol, EHubUniverse: eHubUniverse, EHubSource: eHubSource})
     }
 
     return raws, nil
@@ -3,6 +3,7 @@ package config
 import (
     "fmt"
     "github.com/xuri/excelize/v2"
+    "guitarHetic/internal/domain/artnet"
     "log"
     "strconv"
 )
@@ -27,20 +28,27 @@ func LoadPatchMapFromExcel(path string) (map[int]map[int][]int, error) {
         return nil, fmt.Errorf("impossible de lire les lignes de la feuille '%s': %w", sheetName, err)
     }
 
+    headerRow := findHeaderRow(rows)
+    if headerRow < 0 {
+        return nil, fmt.Errorf("la feuille '%s' est vide", sheetName)
+    }
+    columns, err := resolveColumns(rows[headerRow], patchColumns)
+    if err != nil {
+        return nil, fmt.Errorf("en-tête du fichier de patch invalide: %w", err)
+    }
+
     patchMap := make(map[int]map[int][]int)
 
-    for i, row := range rows {
-        if i == 0 {
-            continue
-        }
-        if len(row) < 3 {
-            log.Printf("Patch Loader: Ligne %d ignorée (pas assez de colonnes)", i+1)
+    for i := headerRow + 1; i < len(rows); i++ {
+        row := rows[i]
+        if isEmptyRow(row) {
             continue
         }
 
-        universe, errU := strconv.Atoi(row[0])
-        source, errS := strconv.Atoi(row[1])
-        destination, errD := strconv.Atoi(row[2])
+        address, errU := artnet.ParsePortAddress(columns.value(row, colPatchUniverse))
+        universe := int(address)
+        source, errS := strconv.Atoi(columns.value(row, colPatchSource))
+        destination, errD := strconv.Atoi(columns.value(row, colPatchDestination))
 
         if errU != nil || errS != nil || errD != nil {
             log.Printf("Patch Loader: Ligne %d ignorée (format de nombre invalide)", i+1)
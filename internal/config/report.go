@@ -0,0 +1,79 @@
+package config
+
+import (
+    "fmt"
+    "log"
+)
+
+type Severity int
+
+const (
+    SeverityWarning Severity = iota
+    SeverityError
+)
+
+func (s Severity) String() string {
+    if s == SeverityError {
+        return "erreur"
+    }
+    return "avertissement"
+}
+
+type Issue struct {
+    Row      int
+    Column   string
+    Reason   string
+    Severity Severity
+}
+
+func (i Issue) String() string {
+    location := fmt.Sprintf("Ligne %d", i.Row)
+    if i.Row == 0 {
+        location = "Fichier"
+    }
+    if i.Column != "" {
+        location += fmt.Sprintf(", colonne '%s'", i.Column)
+    }
+    return fmt.Sprintf("%s : %s (%s)", location, i.Reason, i.Severity)
+}
+
+type Report struct {
+    Path        string
+    RowsRead    int
+    RowsSkipped int
+    Issues      []Issue
+}
+
+func newReport(path string) *Report {
+    return &Report{Path: path}
+}
+
+func (r *Report) add(row int, column string, severity Severity, format string, args ...any) {
+    issue := Issue{Row: row, Column: column, Reason: fmt.Sprintf(format, args...), Severity: severity}
+    r.Issues = append(r.Issues, issue)
+    log.Printf("Config Loader: %s", issue)
+}
+
+func (r *Report) skip(row int, column string, format string, args ...any) {
+    r.RowsSkipped++
+    r.add(row, column, SeverityError, format, args...)
+}
+
+func (r *Report) HasIssues() bool {
+    return r != nil && len(r.Issues) > 0
+}
+
+func (r *Report) Count(severity Severity) int {
+    n := 0
+    for _, issue := range r.Issues {
+        if issue.Severity == severity {
+            n++
+        }
+    }
+    return n
+}
+
+func (r *Report) Summary() string {
+    return fmt.Sprintf("%d ligne(s) lue(s), %d ignorée(s), %d erreur(s), %d avertissement(s)",
+        r.RowsRead, r.RowsSkipped, r.Count(SeverityError), r.Count(SeverityWarning))
+}
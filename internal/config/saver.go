@@ -2,77 +2,158 @@ package config
 
 import (
     "github.com/xuri/excelize/v2"
+    "guitarHetic/internal/domain/output"
+    "guitarHetic/internal/domain/pixel"
+    "slices"
     "sort"
+    "strconv"
 )
 
 func Save(cfg *Config, path string) error {
+    // Sans univers dans la clé, une plage qui déborde redevient une ligne Spill.
     type groupKey struct {
-        Name     string
-        IP       string
-        Universe int
+        Name         string
+        IP           string
+        Protocol     output.Protocol
+        Format       pixel.Format
+        EHubUniverse int
+        EHubSource   string
     }
-    groups := make(map[groupKey][]int)
+    groups := make(map[groupKey][]RoutingEntry)
     for _, entry := range cfg.RoutingTable {
-        key := groupKey{Name: entry.Name, IP: entry.IP, Universe: entry.Universe}
-        groups[key] = append(groups[key], entry.EntityID)
+        key := groupKey{Name: entry.Name, IP: entry.IP, Protocol: entry.Protocol, Format: entry.Format, EHubUniverse: entry.EHubUniverse, EHubSource: entry.EHubSource}
+        groups[key] = append(groups[key], entry)
     }
 
     type outputRow struct {
-        Name     string
-        Start    int
-        End      int
-        IP       string
-        Universe int
+        Name         string
+        Start        int
+        End          int
+        IP           string
+        Protocol     output.Protocol
+        Universe     int
+        DMXStart     int
+        Format       pixel.Format
+        Spill        bool
+        EHubUniverse int
+        EHubSource   string
     }
     var outputRows []outputRow
 
-    for key, ids := range groups {
-        if len(ids) == 0 {
+    for key, entries := range groups {
+        if len(entries) == 0 {
             continue
         }
-        sort.Ints(ids)
+        sort.Slice(entries, func(i, j int) bool { return entries[i].EntityID < entries[j].EntityID })
 
-        start, end := ids[0], ids[0]
-        for i := 1; i < len(ids); i++ {
-            if ids[i] == end+1 {
-                end = ids[i]
+        channels := key.Format.Channels()
+        follows := func(last, entry RoutingEntry) bool {
+            if entry.EntityID != last.EntityID+1 {
+                return false
+            }
+            if entry.Universe == last.Universe {
+                return entry.DMXOffset == last.DMXOffset+channels
+            }
+            if entry.Universe != last.Universe+1 || entry.DMXOffset != 0 || last.DMXOffset+2*channels <= 512 {
+                return false
+            }
+            // Une ligne Spill donne les mêmes réglages à tous ses univers.
+            lastKey, nextKey := last.Key(), entry.Key()
+            lastOut, nextOut := cfg.Universes[lastKey], cfg.Universes[nextKey]
+            return lastOut.Physical == nextOut.Physical && lastOut.FrameLength == nextOut.FrameLength &&
+                slices.Equal(targetsFrom(cfg.UniverseTargets[lastKey], key.IP), targetsFrom(cfg.UniverseTargets[nextKey], key.IP))
+        }
+        newRow := func(first, last RoutingEntry) outputRow {
+            return outputRow{Name: key.Name, Start: first.EntityID, End: last.EntityID, IP: key.IP, Protocol: key.Protocol, Universe: first.Universe, DMXStart: first.DMXOffset + 1, Format: key.Format, Spill: last.Universe != first.Universe, EHubUniverse: key.EHubUniverse, EHubSource: key.EHubSource}
+        }
+
+        first, last := entries[0], entries[0]
+        for _, entry := range entries[1:] {
+            if follows(last, entry) {
+                last = entry
             } else {
-                outputRows = append(outputRows, outputRow{Name: key.Name, Start: start, End: end, IP: key.IP, Universe: key.Universe})
-                start, end = ids[i], ids[i]
+                outputRows = append(outputRows, newRow(first, last))
+                first, last = entry, entry
             }
         }
-        outputRows = append(outputRows, outputRow{Name: key.Name, Start: start, End: end, IP: key.IP, Universe: key.Universe})
+        outputRows = append(outputRows, newRow(first, last))
     }
 
     sort.Slice(outputRows, func(i, j int) bool {
+        if outputRows[i].Protocol != outputRows[j].Protocol {
+            return outputRows[i].Protocol < outputRows[j].Protocol
+        }
         if outputRows[i].Universe != outputRows[j].Universe {
             return outputRows[i].Universe < outputRows[j].Universe
         }
+        if outputRows[i].DMXStart != outputRows[j].DMXStart {
+            return outputRows[i].DMXStart < outputRows[j].DMXStart
+        }
         return outputRows[i].Start < outputRows[j].Start
     })
 
+    headers := []string{colName, colEntityStart, colEntityEnd, colIP, colUniverse, colDMXStart, colFormat, colSpill, colPhysical, colFrameLength, colProtocol, colEHubUniverse, colEHubSource}
+
+    if isCSVPath(path) {
+        rows := [][]string{headers}
+        for _, rowData := range outputRows {
+            key := output.UniverseKey{Protocol: rowData.Protocol, Universe: rowData.Universe}
+            rows = append(rows, []string{
+                rowData.Name,
+                strconv.Itoa(rowData.Start),
+                strconv.Itoa(rowData.End),
+                FormatTargets(targetsFrom(cfg.UniverseTargets[key], rowData.IP)),
+                strconv.Itoa(rowData.Universe),
+                strconv.Itoa(rowData.DMXStart),
+                rowData.Format.String(),
+                formatBool(rowData.Spill),
+                strconv.Itoa(cfg.Universes[key].Physical),
+                formatOptionalInt(cfg.Universes[key].FrameLength),
+                rowData.Protocol.String(),
+                strconv.Itoa(rowData.EHubUniverse),
+                rowData.EHubSource,
+            })
+        }
+        return writeCSVRows(path, rows)
+    }
+
     f := excelize.NewFile()
     sheetName := "Feuil1"
     index, _ := f.NewSheet(sheetName)
     f.SetActiveSheet(index)
 
-    headers := []string{"Name", "Entity Start", "Entity End", "ArtNet IP", "ArtNet Universe"}
     f.SetSheetRow(sheetName, "A1", &headers)
 
     for i, rowData := range outputRows {
+        key := output.UniverseKey{Protocol: rowData.Protocol, Universe: rowData.Universe}
         row := []interface{}{
             rowData.Name,
             rowData.Start,
             rowData.End,
-            rowData.IP,
+            FormatTargets(targetsFrom(cfg.UniverseTargets[key], rowData.IP)),
             rowData.Universe,
+            rowData.DMXStart,
+            rowData.Format.String(),
+            formatBool(rowData.Spill),
+            cfg.Universes[key].Physical,
+            formatOptionalInt(cfg.Universes[key].FrameLength),
+            rowData.Protocol.String(),
+            rowData.EHubUniverse,
+            rowData.EHubSource,
         }
         cell, _ := excelize.CoordinatesToCellName(1, i+2)
         f.SetSheetRow(sheetName, cell, &row)
     }
 
-    f.SetColWidth(sheetName, "A", "E", 20)
+    f.SetColWidth(sheetName, "A", "M", 20)
     f.DeleteSheet("Sheet1")
 
     return f.SaveAs(path)
 }
+
+func formatOptionalInt(v int) string {
+    if v == 0 {
+        return ""
+    }
+    return strconv.Itoa(v)
+}
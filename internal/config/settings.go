@@ -0,0 +1,117 @@
+package config
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io/fs"
+    "os"
+    "path/filepath"
+)
+
+type ArtSyncSettings struct {
+    Enabled bool   `json:"enabled"`
+    Address string `json:"address"`
+}
+
+// Désactivé, les cibles "broadcast:" du routage ne reçoivent plus rien.
+type BroadcastSettings struct {
+    Enabled bool `json:"enabled"`
+}
+
+// Output : nom d'interface ou adresse IPv4 locale, vide pour laisser le système choisir.
+type NetworkSettings struct {
+    Output string `json:"output"`
+}
+
+// Address : nom d'interface ou adresse IPv4 locale, vide pour toutes.
+type EHubSettings struct {
+    Address string `json:"address"`
+    Port    int    `json:"port"`
+}
+
+const DefaultEHubPort = 8765
+
+// Policy vaut "ltp", "htp" ou "priority" ; Priorities est indexée par IP d'émetteur.
+type MergeSettings struct {
+    Policy     string         `json:"policy"`
+    TimeoutMs  int            `json:"timeout_ms"`
+    Priorities map[string]int `json:"priorities"`
+}
+
+// TimeoutMs à 0 désactive la détection ; Action vaut "hold", "fade" ou "pattern".
+type InputLossSettings struct {
+    TimeoutMs   int     `json:"timeout_ms"`
+    Action      string  `json:"action"`
+    FadeSeconds float64 `json:"fade_seconds"`
+    Pattern     string  `json:"pattern"`
+}
+
+type DiscoverySettings struct {
+    Address string `json:"address"`
+}
+
+// Sans CID, un identifiant stable est dérivé du nom de la machine.
+type SACNSettings struct {
+    SourceName string `json:"source_name"`
+    CID        string `json:"cid"`
+    Priority   int    `json:"priority"`
+}
+
+type Settings struct {
+    ArtSync   ArtSyncSettings   `json:"artsync"`
+    Discovery DiscoverySettings `json:"discovery"`
+    SACN      SACNSettings      `json:"sacn"`
+    Broadcast BroadcastSettings `json:"broadcast"`
+    Network   NetworkSettings   `json:"network"`
+    EHub      EHubSettings      `json:"ehub"`
+    Merge     MergeSettings     `json:"merge"`
+    InputLoss InputLossSettings `json:"input_loss"`
+}
+
+func DefaultSettings() Settings {
+    return Settings{
+        ArtSync:   ArtSyncSettings{Enabled: false, Address: "255.255.255.255"},
+        Discovery: DiscoverySettings{Address: "255.255.255.255"},
+        SACN:      SACNSettings{SourceName: "Guitare Hetic", Priority: 100},
+        Broadcast: BroadcastSettings{Enabled: true},
+        EHub:      EHubSettings{Port: DefaultEHubPort},
+        Merge:     MergeSettings{Policy: "ltp", TimeoutMs: 3000},
+        InputLoss: InputLossSettings{TimeoutMs: 5000, Action: "hold", FadeSeconds: 2, Pattern: "black"},
+    }
+}
+
+func DefaultSettingsPath() string {
+    dir, err := os.UserConfigDir()
+    if err != nil {
+        return "settings.json"
+    }
+    return filepath.Join(dir, "guitarHetic", "settings.json")
+}
+
+// LoadSettings renvoie les réglages par défaut si le fichier n'existe pas encore.
+func LoadSettings(path string) (Settings, error) {
+    settings := DefaultSettings()
+    data, err := os.ReadFile(path)
+    if errors.Is(err, fs.ErrNotExist) {
+        return settings, nil
+    }
+    if err != nil {
+        return settings, err
+    }
+    if err := json.Unmarshal(data, &settings); err != nil {
+        return DefaultSettings(), fmt.Errorf("fichier de réglages '%s' invalide: %w", path, err)
+    }
+    return settings, nil
+}
+
+func SaveSettings(path string, settings Settings) error {
+    data, err := json.MarshalIndent(settings, "", "  ")
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return err
+    }
+    return os.WriteFile(path, data, 0o644)
+}
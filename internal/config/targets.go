@@ -0,0 +1,120 @@
+package config
+
+import (
+    "fmt"
+    "guitarHetic/internal/domain/output"
+    "net"
+    "strings"
+)
+
+const broadcastPrefix = "broadcast:"
+
+// La première adresse est le contrôleur, les suivantes ses miroirs.
+func ParseTargets(s string) ([]output.Target, error) {
+    fields := strings.FieldsFunc(s, func(r rune) bool {
+        return r == ',' || r == '|' || r == ' ' || r == '\t'
+    })
+    if len(fields) == 0 {
+        return nil, fmt.Errorf("aucune adresse de destination")
+    }
+
+    targets := make([]output.Target, 0, len(fields))
+    for _, field := range fields {
+        var target output.Target
+        if len(field) > len(broadcastPrefix) && strings.EqualFold(field[:len(broadcastPrefix)], broadcastPrefix) {
+            target.Broadcast = true
+            field = field[len(broadcastPrefix):]
+        }
+        switch {
+        case strings.EqualFold(field, MulticastIP):
+            if target.Broadcast {
+                return nil, fmt.Errorf("adresse invalide: '%s'", broadcastPrefix+field)
+            }
+            target.Address = MulticastIP
+        case net.ParseIP(field).To4() != nil:
+            target.Address = net.ParseIP(field).To4().String()
+        default:
+            return nil, fmt.Errorf("adresse invalide: '%s'", field)
+        }
+        targets = mergeTargets(targets, []output.Target{target})
+    }
+    return targets, nil
+}
+
+func FormatTargets(targets []output.Target) string {
+    parts := make([]string, len(targets))
+    for i, target := range targets {
+        parts[i] = formatTarget(target)
+    }
+    return strings.Join(parts, ", ")
+}
+
+func formatTarget(target output.Target) string {
+    if target.Broadcast {
+        return broadcastPrefix + target.Address
+    }
+    return target.Address
+}
+
+func mergeTargets(targets, extra []output.Target) []output.Target {
+    for _, candidate := range extra {
+        known := false
+        for _, target := range targets {
+            if target.Address == candidate.Address {
+                known = true
+                break
+            }
+        }
+        if !known {
+            targets = append(targets, candidate)
+        }
+    }
+    return targets
+}
+
+// targetsFrom remet la cible d'adresse first en tête de liste.
+func targetsFrom(targets []output.Target, first string) []output.Target {
+    ordered := make([]output.Target, 0, len(targets))
+    for _, target := range targets {
+        if target.Address == first {
+            ordered = append(ordered, target)
+        }
+    }
+    for _, target := range targets {
+        if target.Address != first {
+            ordered = append(ordered, target)
+        }
+    }
+    return ordered
+}
+
+// Le premier élément devient le contrôleur des entrées de l'univers.
+func (c *Config) SetUniverseTargets(key output.UniverseKey, targets []output.Target) bool {
+    if _, ok := c.UniverseTargets[key]; !ok || len(targets) == 0 {
+        return false
+    }
+    c.UniverseTargets[key] = targets
+    for i, entry := range c.RoutingTable {
+        if entry.Key() == key {
+            c.RoutingTable[i].IP = targets[0].Address
+        }
+    }
+    return true
+}
+
+// ReplaceIP remplace oldIP par newIP partout, contrôleurs comme miroirs.
+func (c *Config) ReplaceIP(oldIP, newIP string) {
+    for i, entry := range c.RoutingTable {
+        if entry.IP == oldIP {
+            c.RoutingTable[i].IP = newIP
+        }
+    }
+    for u, targets := range c.UniverseTargets {
+        for i, target := range targets {
+            if target.Address == oldIP {
+                targets[i].Address = newIP
+            }
+        }
+        c.UniverseTargets[u] = mergeTargets(nil, targets)
+    }
+}
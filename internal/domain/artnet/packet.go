@@ -2,14 +2,54 @@ package artnet
 
 import "encoding/binary"
 
-func BuildArtNetHeader(universe int) []byte {
+const (
+    sequenceOffset = 12
+    physicalOffset = 13
+)
+
+func BuildArtNetHeader(universe, physical, length int) []byte {
     header := make([]byte, 18)
-    copy(header[0:8], []byte("Art-Net\x00"))
-    binary.LittleEndian.PutUint16(header[8:10], 0x5000)
+    copy(header[0:8], artNetID)
+    binary.LittleEndian.PutUint16(header[8:10], OpDmx)
     binary.BigEndian.PutUint16(header[10:12], 14)
-    header[12] = 0
-    header[13] = 0
-    binary.LittleEndian.PutUint16(header[14:16], uint16(universe))
-    binary.BigEndian.PutUint16(header[16:18], 512)
+    header[sequenceOffset] = 0
+    header[physicalOffset] = byte(physical)
+    address := PortAddress(universe) & MaxPortAddress
+    header[14] = address.SubUni()
+    header[15] = byte(address.Net())
+    binary.BigEndian.PutUint16(header[16:18], uint16(FrameLength(length)))
     return header
 }
+
+// FrameLength renvoie la longueur de données ArtDmx couvrant highestChannel :
+// le protocole impose une longueur paire comprise entre 2 et 512.
+func FrameLength(highestChannel int) int {
+    if highestChannel < 2 {
+        return 2
+    }
+    if highestChannel > 512 {
+        return 512
+    }
+    return highestChannel + highestChannel%2
+}
+
+func SetSequence(packet []byte, sequence byte) {
+    packet[sequenceOffset] = sequence
+}
+
+// NextSequence fait tourner le compteur de 1 à 255 : la valeur 0 signifie
+// pour le récepteur que le séquencement est désactivé.
+func NextSequence(sequence byte) byte {
+    if sequence == 255 {
+        return 1
+    }
+    return sequence + 1
+}
+
+func BuildArtSyncPacket() []byte {
+    packet := make([]byte, 14)
+    copy(packet[0:8], artNetID)
+    binary.LittleEndian.PutUint16(packet[8:10], OpSync)
+    binary.BigEndian.PutUint16(packet[10:12], 14)
+    return packet
+}
@@ -0,0 +1,168 @@
+package artnet
+
+import (
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "net"
+)
+
+const (
+    OpPoll      = 0x2000
+    OpPollReply = 0x2100
+    OpDmx       = 0x5000
+    OpSync      = 0x5200
+
+    artPollReplyMinSize = 207
+    artPollReplySize    = 239
+
+    StyleNode       = 0x00
+    StyleController = 0x01
+)
+
+var artNetID = []byte("Art-Net\x00")
+
+type NodeInfo struct {
+    IP              net.IP
+    Port            uint16
+    FirmwareVersion uint16
+    NetSwitch       byte
+    SubSwitch       byte
+    Oem             uint16
+    ShortName       string
+    LongName        string
+    NodeReport      string
+    NumPorts        int
+    PortTypes       [4]byte
+    SwIn            [4]byte
+    SwOut           [4]byte
+    Style           byte
+    MAC             net.HardwareAddr
+    BindIndex       byte
+}
+
+func (n NodeInfo) OutputPortAddresses() []PortAddress {
+    return n.portAddresses(0x80, n.SwOut)
+}
+
+func (n NodeInfo) InputPortAddresses() []PortAddress {
+    return n.portAddresses(0x40, n.SwIn)
+}
+
+func (n NodeInfo) portAddresses(direction byte, switches [4]byte) []PortAddress {
+    var addresses []PortAddress
+    for i := 0; i < n.NumPorts && i < 4; i++ {
+        if n.PortTypes[i]&direction == 0 {
+            continue
+        }
+        address := int(n.NetSwitch&0x7F)<<8 | int(n.SubSwitch&0x0F)<<4 | int(switches[i]&0x0F)
+        addresses = append(addresses, PortAddress(address))
+    }
+    return addresses
+}
+
+func (n NodeInfo) FirmwareString() string {
+    return fmt.Sprintf("%d.%d", n.FirmwareVersion>>8, n.FirmwareVersion&0xFF)
+}
+
+// OpCode renvoie le code d'opération d'un paquet Art-Net, ou une erreur si
+// le paquet ne commence pas par l'identifiant "Art-Net".
+func OpCode(packet []byte) (uint16, error) {
+    if len(packet) < 10 || !bytes.Equal(packet[0:8], artNetID) {
+        return 0, fmt.Errorf("paquet non Art-Net")
+    }
+    return binary.LittleEndian.Uint16(packet[8:10]), nil
+}
+
+func BuildArtPollPacket() []byte {
+    packet := make([]byte, 14)
+    copy(packet[0:8], artNetID)
+    binary.LittleEndian.PutUint16(packet[8:10], OpPoll)
+    binary.BigEndian.PutUint16(packet[10:12], 14)
+    // Flags : demande aux nœuds de répondre aussi en cas de changement d'état.
+    packet[12] = 0x02
+    packet[13] = 0
+    return packet
+}
+
+func ParseArtPollReply(packet []byte) (NodeInfo, error) {
+    op, err := OpCode(packet)
+    if err != nil {
+        return NodeInfo{}, err
+    }
+    if op != OpPollReply {
+        return NodeInfo{}, fmt.Errorf("OpCode 0x%04x inattendu pour un ArtPollReply", op)
+    }
+    if len(packet) < artPollReplyMinSize {
+        return NodeInfo{}, fmt.Errorf("ArtPollReply trop court (%d octets)", len(packet))
+    }
+
+    node := NodeInfo{
+        IP:              net.IPv4(packet[10], packet[11], packet[12], packet[13]),
+        Port:            binary.LittleEndian.Uint16(packet[14:16]),
+        FirmwareVersion: binary.BigEndian.Uint16(packet[16:18]),
+        NetSwitch:       packet[18],
+        SubSwitch:       packet[19],
+        Oem:             binary.BigEndian.Uint16(packet[20:22]),
+        ShortName:       cString(packet[26:44]),
+        LongName:        cString(packet[44:108]),
+        NodeReport:      cString(packet[108:172]),
+        NumPorts:        int(binary.BigEndian.Uint16(packet[172:174])),
+        Style:           packet[200],
+        MAC:             net.HardwareAddr(append([]byte(nil), packet[201:207]...)),
+    }
+    copy(node.PortTypes[:], packet[174:178])
+    copy(node.SwIn[:], packet[186:190])
+    copy(node.SwOut[:], packet[190:194])
+    if len(packet) > 211 {
+        node.BindIndex = packet[211]
+    }
+    return node, nil
+}
+
+// BuildArtPollReply encode un ArtPollReply décrivant jusqu'à 4 ports.
+// Les ports d'entrée sont signalés comme recevant des données.
+func BuildArtPollReply(node NodeInfo) []byte {
+    packet := make([]byte, artPollReplySize)
+    copy(packet[0:8], artNetID)
+    binary.LittleEndian.PutUint16(packet[8:10], OpPollReply)
+    ip := node.IP.To4()
+    if ip != nil {
+        copy(packet[10:14], ip)
+    }
+    binary.LittleEndian.PutUint16(packet[14:16], node.Port)
+    binary.BigEndian.PutUint16(packet[16:18], node.FirmwareVersion)
+    packet[18] = node.NetSwitch & 0x7F
+    packet[19] = node.SubSwitch & 0x0F
+    binary.BigEndian.PutUint16(packet[20:22], node.Oem)
+    packet[23] = 0xC0
+    copy(packet[26:43], node.ShortName)
+    copy(packet[44:107], node.LongName)
+    copy(packet[108:171], node.NodeReport)
+    binary.BigEndian.PutUint16(packet[172:174], uint16(node.NumPorts))
+    copy(packet[174:178], node.PortTypes[:])
+    for i := 0; i < node.NumPorts && i < 4; i++ {
+        if node.PortTypes[i]&0x40 != 0 {
+            packet[178+i] = 0x80
+        }
+        if node.PortTypes[i]&0x80 != 0 {
+            packet[182+i] = 0x80
+        }
+    }
+    copy(packet[186:190], node.SwIn[:])
+    copy(packet[190:194], node.SwOut[:])
+    packet[200] = node.Style
+    copy(packet[201:207], node.MAC)
+    if ip != nil {
+        copy(packet[207:211], ip)
+    }
+    packet[211] = node.BindIndex
+    return packet
+}
+
+func cString(b []byte) string {
+    if i := bytes.IndexByte(b, 0); i >= 0 {
+        b = b[:i]
+    }
+    return string(b)
+}
@@ -0,0 +1,80 @@
+package artnet
+
+import (
+    "fmt"
+    "guitarHetic/internal/domain/output"
+    "strconv"
+    "strings"
+)
+
+// Protocol est le nom sous lequel le transport Art-Net s'enregistre.
+const Protocol output.Protocol = "Art-Net"
+
+// PortAddress est l'adresse 15 bits d'un univers Art-Net :
+// Net (7 bits), SubNet (4 bits) et Universe (4 bits).
+type PortAddress uint16
+
+const MaxPortAddress = 0x7FFF
+
+func NewPortAddress(net, subNet, universe int) (PortAddress, error) {
+    if net < 0 || net > 127 {
+        return 0, fmt.Errorf("Net hors plage (0-127): %d", net)
+    }
+    if subNet < 0 || subNet > 15 {
+        return 0, fmt.Errorf("SubNet hors plage (0-15): %d", subNet)
+    }
+    if universe < 0 || universe > 15 {
+        return 0, fmt.Errorf("Universe hors plage (0-15): %d", universe)
+    }
+    return PortAddress(net<<8 | subNet<<4 | universe), nil
+}
+
+// ParsePortAddress accepte la forme "net.subnet.universe" (ou "net:subnet:universe")
+// et la forme plate "0" à "32767".
+func ParsePortAddress(s string) (PortAddress, error) {
+    s = strings.TrimSpace(s)
+    parts := strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == ':' })
+    switch len(parts) {
+    case 1:
+        flat, err := strconv.Atoi(parts[0])
+        if err != nil {
+            return 0, fmt.Errorf("univers invalide: '%s'", s)
+        }
+        if flat < 0 || flat > MaxPortAddress {
+            return 0, fmt.Errorf("univers hors plage (0-%d): %d", MaxPortAddress, flat)
+        }
+        return PortAddress(flat), nil
+    case 3:
+        values := make([]int, 3)
+        for i, part := range parts {
+            v, err := strconv.Atoi(part)
+            if err != nil {
+                return 0, fmt.Errorf("univers invalide: '%s'", s)
+            }
+            values[i] = v
+        }
+        return NewPortAddress(values[0], values[1], values[2])
+    default:
+        return 0, fmt.Errorf("univers invalide: '%s' (attendu net.subnet.universe ou 0-%d)", s, MaxPortAddress)
+    }
+}
+
+func (p PortAddress) Net() int {
+    return int(p>>8) & 0x7F
+}
+
+func (p PortAddress) SubNet() int {
+    return int(p>>4) & 0x0F
+}
+
+func (p PortAddress) Universe() int {
+    return int(p) & 0x0F
+}
+
+func (p PortAddress) SubUni() byte {
+    return byte(p)
+}
+
+func (p PortAddress) String() string {
+    return fmt.Sprintf("%d.%d.%d", p.Net(), p.SubNet(), p.Universe())
+}
@@ -10,6 +10,8 @@ type RawPacket struct {
 	From *net.UDPAddr 
 }
 
+// EHubConfigRange associe les sextuors SextuorStart..SextuorEnd des messages
+// update aux entités EntityStart..EntityEnd, dans l'ordre.
 type EHubConfigRange struct {
 	SextuorStart uint16
 	EntityStart  uint16
@@ -17,7 +19,28 @@ type EHubConfigRange struct {
 	EntityEnd    uint16
 }
 
+// Len renvoie le nombre de sextuors effectivement associés : si les deux
+// intervalles n'ont pas la même taille, le plus court l'emporte.
+func (r EHubConfigRange) Len() int {
+	if r.SextuorEnd < r.SextuorStart || r.EntityEnd < r.EntityStart {
+		return 0
+	}
+	return min(int(r.SextuorEnd-r.SextuorStart), int(r.EntityEnd-r.EntityStart)) + 1
+}
+
+// Entity renvoie l'entité pilotée par le sextuor d'index sextuor.
+func (r EHubConfigRange) Entity(sextuor int) (int, bool) {
+	offset := sextuor - int(r.SextuorStart)
+	if offset < 0 || offset >= r.Len() {
+		return 0, false
+	}
+	return int(r.EntityStart) + offset, true
+}
+
+// Source est l'adresse IP de l'émetteur, vide pour les messages produits
+// en interne (Faker).
 type EHubConfigMsg struct {
+	Source   string
 	Universe int
 	Ranges   []EHubConfigRange
 }
@@ -26,6 +49,8 @@ func (m EHubConfigMsg) String() string {
 	return fmt.Sprintf("Message Config [Univers eHuB: %d, %d plages]", m.Universe, len(m.Ranges))
 }
 
+// EHubEntityState est un sextuor : ID est son index, que la configuration
+// de l'univers traduit en entité.
 type EHubEntityState struct {
 	ID    uint16
 	Red   byte
@@ -35,6 +60,7 @@ type EHubEntityState struct {
 }
 
 type EHubUpdateMsg struct {
+	Source   string
 	Universe int
 	Entities []EHubEntityState
 }
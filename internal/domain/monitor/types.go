@@ -0,0 +1,16 @@
+package monitor
+
+import (
+    "guitarHetic/internal/domain/ehub"
+    "guitarHetic/internal/domain/output"
+)
+
+type UniverseMonitorData struct {
+    Universe   output.UniverseKey
+    InputState []ehub.EHubEntityState
+    OutputDMX  [512]byte
+}
+
+type Publisher interface {
+    Publish(data *UniverseMonitorData)
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+    "cmp"
+    "fmt"
+    "sync/atomic"
+)
+
+// UniverseKey identifie un univers émis : Art-Net et sACN numérotent leurs
+// univers chacun de leur côté, l'univers 1 de l'un n'est pas celui de l'autre.
+type UniverseKey struct {
+    Protocol Protocol
+    Universe int
+}
+
+func (k UniverseKey) String() string {
+    return fmt.Sprintf("%s %d", k.Protocol, k.Universe)
+}
+
+// CompareUniverseKeys ordonne les univers par protocole puis par numéro.
+func CompareUniverseKeys(a, b UniverseKey) int {
+    if a.Protocol != b.Protocol {
+        return cmp.Compare(a.Protocol, b.Protocol)
+    }
+    return cmp.Compare(a.Universe, b.Universe)
+}
+
+// Frame est l'état DMX complet d'un univers, produit par le processeur.
+type Frame struct {
+    Protocol Protocol
+    Universe int
+    Data     [512]byte
+}
+
+func (f Frame) Key() UniverseKey {
+    return UniverseKey{Protocol: f.Protocol, Universe: f.Universe}
+}
+
+// Target est une adresse de réception d'un univers. Broadcast signale une
+// adresse de diffusion, à laquelle aucun nœud ne répond individuellement.
+type Target struct {
+    Address   string
+    Broadcast bool
+}
+
+// Destination décrit un univers à émettre par un transport, vers une ou
+// plusieurs cibles (le contrôleur puis ses miroirs).
+type Destination struct {
+    Universe    int
+    Targets     []Target
+    Physical    int
+    FrameLength int
+}
+
+type Stats struct {
+    Frames uint64
+    Bytes  uint64
+    Errors uint64
+}
+
+// Output est un transport de trames DMX (Art-Net, sACN, enregistreur, sink de test...).
+type Output interface {
+    Open(destinations []Destination) error
+    WriteFrame(universe int, data []byte) error
+    Close() error
+    Stats() Stats
+}
+
+// Syncer est implémenté par les transports qui signalent la fin de chaque
+// rafale de trames, comme l'ArtSync d'Art-Net.
+type Syncer interface {
+    Sync() error
+}
+
+// Counters accumule les statistiques d'un transport ; Snapshot peut être
+// appelé depuis une autre goroutine que celle qui écrit.
+type Counters struct {
+    frames atomic.Uint64
+    bytes  atomic.Uint64
+    errors atomic.Uint64
+}
+
+func (c *Counters) Record(size int, err error) {
+    if err != nil {
+        c.errors.Add(1)
+        return
+    }
+    c.frames.Add(1)
+    c.bytes.Add(uint64(size))
+}
+
+func (c *Counters) Snapshot() Stats {
+    return Stats{Frames: c.frames.Load(), Bytes: c.bytes.Load(), Errors: c.errors.Load()}
+}
@@ -0,0 +1,77 @@
+package output
+
+import (
+    "fmt"
+    "slices"
+    "strings"
+    "sync"
+)
+
+// Protocol est le nom sous lequel un transport de sortie s'enregistre.
+type Protocol string
+
+func (p Protocol) String() string {
+    return string(p)
+}
+
+// ProtocolSpec décrit ce que le fichier de routage doit savoir d'un transport.
+type ProtocolSpec struct {
+    Name    Protocol
+    Aliases []string
+    // ParseUniverse lit la colonne univers d'une ligne de ce protocole.
+    ParseUniverse func(s string) (int, error)
+    LastUniverse  int
+    Multicast     bool
+}
+
+var (
+    protocolsMu sync.RWMutex
+    protocols   = make(map[Protocol]ProtocolSpec)
+    protocolIDs = make(map[string]Protocol)
+)
+
+// RegisterProtocol est appelé par le Register de l'infrastructure.
+func RegisterProtocol(spec ProtocolSpec) {
+    protocolsMu.Lock()
+    defer protocolsMu.Unlock()
+    if _, exists := protocols[spec.Name]; exists {
+        panic(fmt.Sprintf("output: protocole %s déjà enregistré", spec.Name))
+    }
+    protocols[spec.Name] = spec
+    for _, name := range append([]string{string(spec.Name)}, spec.Aliases...) {
+        protocolIDs[strings.ToUpper(name)] = spec.Name
+    }
+}
+
+func LookupProtocol(p Protocol) (ProtocolSpec, bool) {
+    protocolsMu.RLock()
+    defer protocolsMu.RUnlock()
+    spec, ok := protocols[p]
+    return spec, ok
+}
+
+// Protocols renvoie, triés, les protocoles enregistrés.
+func Protocols() []Protocol {
+    protocolsMu.RLock()
+    defer protocolsMu.RUnlock()
+    names := make([]Protocol, 0, len(protocols))
+    for name := range protocols {
+        names = append(names, name)
+    }
+    slices.Sort(names)
+    return names
+}
+
+func ParseProtocol(s string) (Protocol, error) {
+    protocolsMu.RLock()
+    p, ok := protocolIDs[strings.ToUpper(strings.TrimSpace(s))]
+    protocolsMu.RUnlock()
+    if !ok {
+        names := make([]string, 0)
+        for _, name := range Protocols() {
+            names = append(names, string(name))
+        }
+        return "", fmt.Errorf("protocole de sortie inconnu: '%s' (attendu %s)", s, strings.Join(names, " ou "))
+    }
+    return p, nil
+}
@@ -0,0 +1,110 @@
+package pixel
+
+import (
+    "fmt"
+    "strings"
+)
+
+type Format int
+
+const (
+    RGB Format = iota
+    GRB
+    BGR
+    RGBW
+    GRBW
+    Mono
+)
+
+var formatNames = map[Format]string{
+    RGB:  "RGB",
+    GRB:  "GRB",
+    BGR:  "BGR",
+    RGBW: "RGBW",
+    GRBW: "GRBW",
+    Mono: "MONO",
+}
+
+var formatAliases = map[string]Format{
+    "W":      Mono,
+    "SINGLE": Mono,
+    "DIMMER": Mono,
+}
+
+func ParseFormat(s string) (Format, error) {
+    name := strings.ToUpper(strings.TrimSpace(s))
+    if name == "" {
+        return RGB, nil
+    }
+    for f, n := range formatNames {
+        if n == name {
+            return f, nil
+        }
+    }
+    if f, ok := formatAliases[name]; ok {
+        return f, nil
+    }
+    return RGB, fmt.Errorf("format de pixel inconnu: '%s'", s)
+}
+
+func (f Format) String() string {
+    if name, ok := formatNames[f]; ok {
+        return name
+    }
+    return fmt.Sprintf("Format(%d)", int(f))
+}
+
+func (f Format) Channels() int {
+    switch f {
+    case RGBW, GRBW:
+        return 4
+    case Mono:
+        return 1
+    default:
+        return 3
+    }
+}
+
+func (f Format) HasWhite() bool {
+    return f == RGBW || f == GRBW
+}
+
+// Encode écrit la couleur dans dst selon l'ordre des canaux du format.
+// dst doit contenir au moins Channels() octets.
+func (f Format) Encode(dst []byte, r, g, b, w byte) {
+    switch f {
+    case GRB:
+        dst[0], dst[1], dst[2] = g, r, b
+    case BGR:
+        dst[0], dst[1], dst[2] = b, g, r
+    case RGBW:
+        dst[0], dst[1], dst[2], dst[3] = r, g, b, w
+    case GRBW:
+        dst[0], dst[1], dst[2], dst[3] = g, r, b, w
+    case Mono:
+        // Sans composante blanche, on prend la composante la plus forte.
+        if w == 0 {
+            w = max(r, g, b)
+        }
+        dst[0] = w
+    default:
+        dst[0], dst[1], dst[2] = r, g, b
+    }
+}
+
+func (f Format) Decode(src []byte) (r, g, b, w byte) {
+    switch f {
+    case GRB:
+        return src[1], src[0], src[2], 0
+    case BGR:
+        return src[2], src[1], src[0], 0
+    case RGBW:
+        return src[0], src[1], src[2], src[3]
+    case GRBW:
+        return src[1], src[0], src[2], src[3]
+    case Mono:
+        return 0, 0, 0, src[0]
+    default:
+        return src[0], src[1], src[2], 0
+    }
+}
@@ -0,0 +1,105 @@
+package sacn
+
+import (
+    "crypto/sha1"
+    "encoding/binary"
+    "encoding/hex"
+    "fmt"
+    "guitarHetic/internal/domain/output"
+    "net"
+    "strconv"
+    "strings"
+)
+
+// Protocol est le nom sous lequel le transport sACN s'enregistre.
+const Protocol output.Protocol = "sACN"
+
+const (
+    Port            = 5568
+    MinUniverse     = 1
+    MaxUniverse     = 63999
+    DefaultPriority = 100
+    MaxPriority     = 200
+
+    // HeaderSize couvre les couches racine, framing et DMP jusqu'au start code inclus.
+    HeaderSize = 126
+
+    sequenceOffset = 111
+)
+
+var acnPacketID = []byte("ASC-E1.17\x00\x00\x00")
+
+// ParseUniverse lit un numéro d'univers sACN, de MinUniverse à MaxUniverse.
+func ParseUniverse(s string) (int, error) {
+    universe, err := strconv.Atoi(strings.TrimSpace(s))
+    if err != nil {
+        return 0, fmt.Errorf("univers sACN invalide: '%s' (attendu %d à %d)", s, MinUniverse, MaxUniverse)
+    }
+    if universe < MinUniverse || universe > MaxUniverse {
+        return 0, fmt.Errorf("univers sACN hors plage (%d-%d): %d", MinUniverse, MaxUniverse, universe)
+    }
+    return universe, nil
+}
+
+// BuildDataHeader encode l'en-tête d'un paquet de données E1.31 pour length
+// créneaux DMX ; les données sont à copier à partir de HeaderSize.
+func BuildDataHeader(cid [16]byte, sourceName string, priority byte, universe, length int) []byte {
+    header := make([]byte, HeaderSize)
+    total := HeaderSize + length
+
+    binary.BigEndian.PutUint16(header[0:2], 0x0010)
+    copy(header[4:16], acnPacketID)
+    binary.BigEndian.PutUint16(header[16:18], flagsAndLength(total-16))
+    binary.BigEndian.PutUint32(header[18:22], 0x00000004)
+    copy(header[22:38], cid[:])
+
+    binary.BigEndian.PutUint16(header[38:40], flagsAndLength(total-38))
+    binary.BigEndian.PutUint32(header[40:44], 0x00000002)
+    copy(header[44:107], sourceName)
+    header[108] = priority
+    binary.BigEndian.PutUint16(header[113:115], uint16(universe))
+
+    binary.BigEndian.PutUint16(header[115:117], flagsAndLength(total-115))
+    header[117] = 0x02
+    header[118] = 0xA1
+    binary.BigEndian.PutUint16(header[121:123], 0x0001)
+    binary.BigEndian.PutUint16(header[123:125], uint16(length+1))
+    return header
+}
+
+func flagsAndLength(length int) uint16 {
+    return 0x7000 | uint16(length)&0x0FFF
+}
+
+// SetSequence écrit le numéro de séquence E1.31 : contrairement à Art-Net,
+// le compteur parcourt 0 à 255 sans valeur réservée.
+func SetSequence(packet []byte, sequence byte) {
+    packet[sequenceOffset] = sequence
+}
+
+// MulticastAddress renvoie le groupe 239.255.x.y réservé à l'univers.
+func MulticastAddress(universe int) net.IP {
+    return net.IPv4(239, 255, byte(universe>>8), byte(universe))
+}
+
+// ParseCID lit un CID au format UUID ("xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx").
+func ParseCID(s string) ([16]byte, error) {
+    var cid [16]byte
+    raw, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(s), "-", ""))
+    if err != nil || len(raw) != len(cid) {
+        return cid, fmt.Errorf("CID sACN invalide: '%s' (attendu un UUID)", s)
+    }
+    copy(cid[:], raw)
+    return cid, nil
+}
+
+// DeriveCID produit un UUID (version 5) stable à partir de seed, pour qu'une
+// même machine garde le même CID d'un lancement à l'autre.
+func DeriveCID(seed string) [16]byte {
+    var cid [16]byte
+    sum := sha1.Sum([]byte("guitarHetic/sacn/" + seed))
+    copy(cid[:], sum[:16])
+    cid[6] = cid[6]&0x0F | 0x50
+    cid[8] = cid[8]&0x3F | 0x80
+    return cid
+}
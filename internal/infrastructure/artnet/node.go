@@ -0,0 +1,341 @@
+package artnet
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "guitarHetic/internal/config"
+    domainArtnet "guitarHetic/internal/domain/artnet"
+    infraOutput "guitarHetic/internal/infrastructure/output"
+    "log"
+    "net"
+    "sort"
+    "sync"
+    "time"
+)
+
+const artNetPort = 6454
+
+const (
+    nodeShortName = "Guitare Hetic"
+    nodeLongName  = "Guitare Hetic - Routeur eHub vers Art-Net"
+    nodeFirmware  = 0x0100
+    portTypeInput = 0x40
+    portsPerReply = 4
+)
+
+// Node possède la socket Art-Net sur le port 6454 : il répond aux ArtPoll
+// pour que les consoles et visualiseurs voient le routeur, et collecte les
+// ArtPollReply des autres nœuds lors d'une découverte. Il est lié à la même
+// interface de sortie que les trames Art-Net.
+type Node struct {
+    mu         sync.Mutex
+    conn       *net.UDPConn
+    sockets    []*net.UDPConn
+    cancel     context.CancelFunc
+    network    string
+    universes  []int
+    collectors map[chan domainArtnet.NodeInfo]struct{}
+}
+
+func NewNode() *Node {
+    return &Node{collectors: make(map[chan domainArtnet.NodeInfo]struct{})}
+}
+
+// Apply lie le nœud à l'interface de sortie des réglages s'il n'y est pas
+// déjà. En cas d'échec, le nœud reste fermé et le prochain Apply réessaie.
+func (n *Node) Apply(ctx context.Context, settings config.Settings) error {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+
+    if n.conn != nil && settings.Network.Output == n.network {
+        return nil
+    }
+    n.closeLocked()
+
+    dialer, err := infraOutput.NewDialer(settings)
+    if err != nil {
+        return err
+    }
+    conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: dialer.LocalIP, Port: artNetPort})
+    if err != nil {
+        return fmt.Errorf("impossible d'écouter sur le port Art-Net %d: %w", artNetPort, err)
+    }
+    sockets := []*net.UDPConn{conn}
+    // Sous Linux, une socket liée à une adresse ne reçoit pas les diffusions :
+    // les ArtPoll diffusés sont lus sur les adresses de diffusion elles-mêmes.
+    if dialer.LocalIP != nil {
+        for _, ip := range []net.IP{infraOutput.DirectedBroadcast(dialer.LocalIP), net.IPv4bcast} {
+            if listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: ip, Port: artNetPort}); err == nil {
+                sockets = append(sockets, listener)
+            }
+        }
+    }
+
+    nodeCtx, cancel := context.WithCancel(ctx)
+    for _, socket := range sockets {
+        go n.serve(nodeCtx, socket, conn, dialer.LocalIP)
+    }
+    n.conn, n.sockets, n.cancel, n.network = conn, sockets, cancel, settings.Network.Output
+    log.Printf("ArtNet Node: À l'écoute des ArtPoll sur %s.", conn.LocalAddr())
+    return nil
+}
+
+func (n *Node) Close() {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    n.closeLocked()
+}
+
+func (n *Node) closeLocked() {
+    if n.conn == nil {
+        return
+    }
+    n.cancel()
+    for _, socket := range n.sockets {
+        socket.Close()
+    }
+    n.conn, n.sockets, n.cancel = nil, nil, nil
+}
+
+// SetUniverses déclare les univers émis par le routeur, annoncés dans les ArtPollReply.
+func (n *Node) SetUniverses(universes []int) {
+    sorted := append([]int(nil), universes...)
+    sort.Ints(sorted)
+    n.mu.Lock()
+    n.universes = sorted
+    n.mu.Unlock()
+}
+
+// serve lit socket jusqu'à sa fermeture ; les réponses partent de conn.
+func (n *Node) serve(ctx context.Context, socket, conn *net.UDPConn, localIP net.IP) {
+    go func() {
+        <-ctx.Done()
+        socket.Close()
+    }()
+
+    buffer := make([]byte, 1024)
+    for {
+        size, from, err := socket.ReadFromUDP(buffer)
+        if err != nil {
+            if errors.Is(err, net.ErrClosed) {
+                log.Println("ArtNet Node: Socket fermée, arrêt.")
+                return
+            }
+            log.Printf("ArtNet Node: Erreur de lecture UDP: %v", err)
+            continue
+        }
+
+        op, err := domainArtnet.OpCode(buffer[:size])
+        if err != nil {
+            continue
+        }
+        switch op {
+        case domainArtnet.OpPoll:
+            n.replyToPoll(conn, from, localIP)
+        case domainArtnet.OpPollReply:
+            if isLocalAddress(from.IP) {
+                continue
+            }
+            node, err := domainArtnet.ParseArtPollReply(buffer[:size])
+            if err != nil {
+                continue
+            }
+            n.dispatchReply(node)
+        }
+    }
+}
+
+func (n *Node) replyToPoll(conn *net.UDPConn, from *net.UDPAddr, bound net.IP) {
+    localIP, mac := localAddressFor(from.IP, bound)
+    if localIP == nil {
+        return
+    }
+    target := &net.UDPAddr{IP: from.IP, Port: artNetPort}
+    for _, reply := range n.buildReplies(localIP, mac) {
+        if _, err := conn.WriteToUDP(reply, target); err != nil {
+            log.Printf("ArtNet Node: Erreur envoi ArtPollReply vers %s: %v", from.IP, err)
+            return
+        }
+    }
+}
+
+// Un ArtPollReply décrit au plus 4 ports partageant Net et SubNet :
+// les univers sont donc regroupés puis répartis sur plusieurs BindIndex.
+func (n *Node) buildReplies(localIP net.IP, mac net.HardwareAddr) [][]byte {
+    n.mu.Lock()
+    universes := n.universes
+    n.mu.Unlock()
+
+    type page struct {
+        net, subNet byte
+        swIn        []byte
+    }
+    var pages []page
+    for _, u := range universes {
+        address := domainArtnet.PortAddress(u)
+        netSwitch, subSwitch := byte(address.Net()), byte(address.SubNet())
+        last := len(pages) - 1
+        if last < 0 || pages[last].net != netSwitch || pages[last].subNet != subSwitch || len(pages[last].swIn) == portsPerReply {
+            pages = append(pages, page{net: netSwitch, subNet: subSwitch})
+            last++
+        }
+        pages[last].swIn = append(pages[last].swIn, byte(address.Universe()))
+    }
+    if len(pages) == 0 {
+        pages = append(pages, page{})
+    }
+
+    replies := make([][]byte, 0, len(pages))
+    for i, p := range pages {
+        info := domainArtnet.NodeInfo{
+            IP:              localIP,
+            Port:            artNetPort,
+            FirmwareVersion: nodeFirmware,
+            NetSwitch:       p.net,
+            SubSwitch:       p.subNet,
+            ShortName:       nodeShortName,
+            LongName:        nodeLongName,
+            NodeReport:      fmt.Sprintf("#0001 [%04d] %d univers émis", i+1, len(universes)),
+            NumPorts:        len(p.swIn),
+            Style:           domainArtnet.StyleController,
+            MAC:             mac,
+            BindIndex:       byte(i + 1),
+        }
+        for port, sw := range p.swIn {
+            info.PortTypes[port] = portTypeInput
+            info.SwIn[port] = sw
+        }
+        replies = append(replies, domainArtnet.BuildArtPollReply(info))
+    }
+    return replies
+}
+
+func (n *Node) dispatchReply(node domainArtnet.NodeInfo) {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    for collector := range n.collectors {
+        select {
+        case collector <- node:
+        default:
+        }
+    }
+}
+
+// Discover diffuse un ArtPoll depuis l'interface du nœud vers l'adresse de découverte et
+// collecte les ArtPollReply reçus pendant timeout. Une adresse de diffusion
+// est refusée quand le garde-fou des réglages la désactive.
+func (n *Node) Discover(ctx context.Context, settings config.Settings, timeout time.Duration) ([]domainArtnet.NodeInfo, error) {
+    broadcastAddress := settings.Discovery.Address
+    target := &net.UDPAddr{IP: net.ParseIP(broadcastAddress), Port: artNetPort}
+    if target.IP == nil {
+        return nil, fmt.Errorf("adresse de découverte invalide: '%s'", broadcastAddress)
+    }
+    if !settings.Broadcast.Enabled && infraOutput.IsBroadcast(target.IP) {
+        return nil, fmt.Errorf("diffusion désactivée dans les réglages, ArtPoll vers %s impossible", broadcastAddress)
+    }
+
+    collector := make(chan domainArtnet.NodeInfo, 256)
+    n.mu.Lock()
+    conn := n.conn
+    n.collectors[collector] = struct{}{}
+    n.mu.Unlock()
+    defer func() {
+        n.mu.Lock()
+        delete(n.collectors, collector)
+        n.mu.Unlock()
+    }()
+
+    if conn == nil {
+        return nil, fmt.Errorf("port Art-Net %d indisponible, découverte impossible", artNetPort)
+    }
+    if _, err := conn.WriteToUDP(domainArtnet.BuildArtPollPacket(), target); err != nil {
+        return nil, fmt.Errorf("impossible d'envoyer l'ArtPoll: %w", err)
+    }
+    log.Printf("ArtNet Node: ArtPoll envoyé vers %s, attente des réponses...", broadcastAddress)
+
+    type nodeKey struct {
+        IP        string
+        BindIndex byte
+    }
+    nodes := make(map[nodeKey]domainArtnet.NodeInfo)
+    timer := time.NewTimer(timeout)
+    defer timer.Stop()
+
+collect:
+    for {
+        select {
+        case node := <-collector:
+            nodes[nodeKey{IP: node.IP.String(), BindIndex: node.BindIndex}] = node
+        case <-timer.C:
+            break collect
+        case <-ctx.Done():
+            break collect
+        }
+    }
+
+    result := make([]domainArtnet.NodeInfo, 0, len(nodes))
+    for _, node := range nodes {
+        result = append(result, node)
+    }
+    sort.Slice(result, func(i, j int) bool {
+        if a, b := result[i].IP.String(), result[j].IP.String(); a != b {
+            return a < b
+        }
+        return result[i].BindIndex < result[j].BindIndex
+    })
+    log.Printf("ArtNet Node: %d nœud(s) ont répondu.", len(result))
+    return result, nil
+}
+
+func isLocalAddress(ip net.IP) bool {
+    addrs, err := net.InterfaceAddrs()
+    if err != nil {
+        return false
+    }
+    for _, addr := range addrs {
+        if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+            return true
+        }
+    }
+    return false
+}
+
+// localAddressFor choisit l'adresse IPv4 locale bound si elle est fixée, sinon
+// celle du même sous-réseau que peer, à défaut la première non loopback.
+func localAddressFor(peer, bound net.IP) (net.IP, net.HardwareAddr) {
+    interfaces, err := net.Interfaces()
+    if err != nil {
+        return nil, nil
+    }
+    var fallbackIP net.IP
+    var fallbackMAC net.HardwareAddr
+    for _, iface := range interfaces {
+        if iface.Flags&net.FlagUp == 0 {
+            continue
+        }
+        addrs, err := iface.Addrs()
+        if err != nil {
+            continue
+        }
+        for _, addr := range addrs {
+            ipNet, ok := addr.(*net.IPNet)
+            if !ok || ipNet.IP.To4() == nil {
+                continue
+            }
+            if bound != nil {
+                if ipNet.IP.Equal(bound) {
+                    return ipNet.IP.To4(), iface.HardwareAddr
+                }
+                continue
+            }
+            if ipNet.Contains(peer) {
+                return ipNet.IP.To4(), iface.HardwareAddr
+            }
+            if fallbackIP == nil && !ipNet.IP.IsLoopback() {
+                fallbackIP, fallbackMAC = ipNet.IP.To4(), iface.HardwareAddr
+            }
+        }
+    }
+    return fallbackIP, fallbackMAC
+}
@@ -0,0 +1,146 @@
+package artnet
+
+import (
+    "errors"
+    "fmt"
+    "guitarHetic/internal/config"
+    domainArtnet "guitarHetic/internal/domain/artnet"
+    domainOutput "guitarHetic/internal/domain/output"
+    infraOutput "guitarHetic/internal/infrastructure/output"
+    "log"
+    "net"
+)
+
+func init() {
+    infraOutput.Register(domainOutput.ProtocolSpec{
+        Name:    domainArtnet.Protocol,
+        Aliases: []string{"ARTNET"},
+        ParseUniverse: func(s string) (int, error) {
+            address, err := domainArtnet.ParsePortAddress(s)
+            return int(address), err
+        },
+        LastUniverse: domainArtnet.MaxPortAddress,
+    }, NewOutput)
+}
+
+// Output émet des paquets ArtDmx, suivis d'un ArtSync par rafale si activé.
+type Output struct {
+    settings     config.ArtSyncSettings
+    dialer       infraOutput.Dialer
+    conns        map[int][]*net.UDPConn
+    headerCache  map[int][]byte
+    frameLengths map[int]int
+    sequences    map[int]byte
+    syncConn     *net.UDPConn
+    syncPacket   []byte
+    counters     domainOutput.Counters
+}
+
+func NewOutput(settings config.Settings) (domainOutput.Output, error) {
+    dialer, err := infraOutput.NewDialer(settings)
+    if err != nil {
+        return nil, err
+    }
+    return &Output{
+        settings:     settings.ArtSync,
+        dialer:       dialer,
+        conns:        make(map[int][]*net.UDPConn),
+        headerCache:  make(map[int][]byte),
+        frameLengths: make(map[int]int),
+        sequences:    make(map[int]byte),
+    }, nil
+}
+
+func (o *Output) Open(destinations []domainOutput.Destination) error {
+    log.Println("ArtNet Output: Initialisation et pré-calcul des en-têtes...")
+    for _, d := range destinations {
+        o.frameLengths[d.Universe] = d.FrameLength
+        o.headerCache[d.Universe] = domainArtnet.BuildArtNetHeader(d.Universe, d.Physical, d.FrameLength)
+
+        // Univers ouvert même si le garde-fou écarte toutes ses cibles.
+        o.conns[d.Universe] = nil
+        for _, target := range d.Targets {
+            conn, err := o.dialer.Dial(target, net.ParseIP(target.Address), artNetPort)
+            if err != nil {
+                o.Close()
+                return err
+            }
+            if conn != nil {
+                o.conns[d.Universe] = append(o.conns[d.Universe], conn)
+            }
+        }
+    }
+    if o.settings.Enabled {
+        ip := net.ParseIP(o.settings.Address)
+        if ip == nil {
+            o.Close()
+            return fmt.Errorf("adresse ArtSync invalide: '%s'", o.settings.Address)
+        }
+        // Une adresse de diffusion passe par le garde-fou, comme les cibles "broadcast:".
+        target := domainOutput.Target{Address: o.settings.Address, Broadcast: infraOutput.IsBroadcast(ip)}
+        conn, err := o.dialer.Dial(target, ip, artNetPort)
+        if err != nil {
+            o.Close()
+            return err
+        }
+        if conn != nil {
+            o.syncConn = conn
+            o.syncPacket = domainArtnet.BuildArtSyncPacket()
+            log.Printf("ArtNet Output: ArtSync activé vers %s.", o.settings.Address)
+        }
+    }
+    log.Printf("ArtNet Output: Initialisé pour %d univers.", len(o.conns))
+    return nil
+}
+
+func (o *Output) WriteFrame(universe int, data []byte) error {
+    conns, ok := o.conns[universe]
+    if !ok {
+        return fmt.Errorf("univers %d non ouvert en Art-Net", universe)
+    }
+
+    length := o.frameLengths[universe]
+    packet := make([]byte, 18+length)
+    copy(packet[0:18], o.headerCache[universe])
+    copy(packet[18:], data[:min(length, len(data))])
+
+    o.sequences[universe] = domainArtnet.NextSequence(o.sequences[universe])
+    domainArtnet.SetSequence(packet, o.sequences[universe])
+
+    var errs []error
+    for _, conn := range conns {
+        _, err := conn.Write(packet)
+        o.counters.Record(len(packet), err)
+        if err != nil {
+            errs = append(errs, err)
+        }
+    }
+    return errors.Join(errs...)
+}
+
+// Sync diffuse l'ArtSync après la rafale : les nœuds en mode synchrone
+// affichent tous les univers de la frame au même instant.
+func (o *Output) Sync() error {
+    if o.syncConn == nil {
+        return nil
+    }
+    _, err := o.syncConn.Write(o.syncPacket)
+    return err
+}
+
+func (o *Output) Stats() domainOutput.Stats {
+    return o.counters.Snapshot()
+}
+
+func (o *Output) Close() error {
+    for _, conns := range o.conns {
+        for _, conn := range conns {
+            conn.Close()
+        }
+    }
+    if o.syncConn != nil {
+        o.syncConn.Close()
+    }
+    log.Println("ArtNet Output: Connexions UDP fermées.")
+    return nil
+}
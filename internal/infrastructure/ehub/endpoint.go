@@ -0,0 +1,70 @@
+package ehub
+
+import (
+    "context"
+    "fmt"
+    "guitarHetic/internal/config"
+    "guitarHetic/internal/domain/ehub"
+    "guitarHetic/internal/infrastructure/netif"
+    "log"
+    "sync"
+)
+
+// Endpoint garde le listener eHub ouvert d'un rechargement de configuration
+// à l'autre : la socket n'est reliée que si l'adresse d'écoute change.
+type Endpoint struct {
+    mu         sync.Mutex
+    packetChan chan<- ehub.RawPacket
+    listener   *Listener
+    cancel     context.CancelFunc
+    settings   config.EHubSettings
+}
+
+func NewEndpoint(packetChan chan<- ehub.RawPacket) *Endpoint {
+    return &Endpoint{packetChan: packetChan}
+}
+
+// Apply ouvre le listener sur settings s'il n'y est pas déjà. En cas
+// d'échec, l'ancien listener est fermé et le prochain Apply réessaie.
+func (e *Endpoint) Apply(ctx context.Context, settings config.EHubSettings) error {
+    e.mu.Lock()
+    defer e.mu.Unlock()
+
+    if e.listener != nil && settings == e.settings {
+        return nil
+    }
+    e.closeLocked()
+
+    if settings.Port <= 0 || settings.Port > 65535 {
+        return fmt.Errorf("port eHub invalide: %d", settings.Port)
+    }
+    bindIP, err := netif.ResolveLocalIP(settings.Address)
+    if err != nil {
+        return fmt.Errorf("adresse d'écoute eHub: %w", err)
+    }
+    listener, err := NewListener(bindIP, settings.Port, e.packetChan)
+    if err != nil {
+        return err
+    }
+
+    listenerCtx, cancel := context.WithCancel(ctx)
+    listener.Start(listenerCtx)
+    e.listener, e.cancel, e.settings = listener, cancel, settings
+    return nil
+}
+
+func (e *Endpoint) Close() {
+    e.mu.Lock()
+    defer e.mu.Unlock()
+    e.closeLocked()
+}
+
+func (e *Endpoint) closeLocked() {
+    if e.listener == nil {
+        return
+    }
+    log.Printf("Infrastructure eHuB: Fermeture du listener (port %d).", e.settings.Port)
+    e.cancel()
+    e.listener.Close()
+    e.listener, e.cancel = nil, nil
+}
@@ -15,18 +15,16 @@ type Listener struct {
     packetChan chan<- ehub.RawPacket
 }
 
-func NewListener(port int, packetChan chan<- ehub.RawPacket) (*Listener, error) {
-    addr, err := net.ResolveUDPAddr("udp", fmt.Sprintf(":%d", port))
-    if err != nil {
-        return nil, fmt.Errorf("impossible de résoudre l'adresse UDP: %w", err)
-    }
+// NewListener écoute sur toutes les interfaces si bindIP est nil.
+func NewListener(bindIP net.IP, port int, packetChan chan<- ehub.RawPacket) (*Listener, error) {
+    addr := &net.UDPAddr{IP: bindIP, Port: port}
 
     conn, err := net.ListenUDP("udp", addr)
     if err != nil {
-        return nil, fmt.Errorf("impossible d'écouter sur le port %d: %w", port, err)
+        return nil, fmt.Errorf("impossible d'écouter sur %s: %w", addr, err)
     }
 
-    log.Printf("Infrastructure eHuB: Listener prêt et à l'écoute sur le port %d", port)
+    log.Printf("Infrastructure eHuB: Listener prêt et à l'écoute sur %s", addr)
 
     return &Listener{
         conn:       conn,
@@ -68,3 +66,8 @@ func (l *Listener) Start(ctx context.Context) {
         }
     }()
 }
+
+// Close libère la socket immédiatement, pour qu'elle puisse être reliée aussitôt.
+func (l *Listener) Close() error {
+    return l.conn.Close()
+}
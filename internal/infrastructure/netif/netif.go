@@ -0,0 +1,70 @@
+package netif
+
+import (
+    "fmt"
+    "net"
+    "strings"
+)
+
+// Interface est une interface réseau active et ses adresses IPv4.
+type Interface struct {
+    Name      string
+    Addresses []net.IP
+}
+
+func List() ([]Interface, error) {
+    interfaces, err := net.Interfaces()
+    if err != nil {
+        return nil, fmt.Errorf("impossible de lister les interfaces réseau: %w", err)
+    }
+    var result []Interface
+    for _, iface := range interfaces {
+        if iface.Flags&net.FlagUp == 0 {
+            continue
+        }
+        addrs, err := iface.Addrs()
+        if err != nil {
+            continue
+        }
+        entry := Interface{Name: iface.Name}
+        for _, addr := range addrs {
+            if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+                entry.Addresses = append(entry.Addresses, ipNet.IP.To4())
+            }
+        }
+        if len(entry.Addresses) > 0 {
+            result = append(result, entry)
+        }
+    }
+    return result, nil
+}
+
+// ResolveLocalIP accepte un nom d'interface ou une adresse IPv4 locale et
+// renvoie l'adresse à laquelle lier une socket. Une valeur vide renvoie nil :
+// le système choisit alors l'interface.
+func ResolveLocalIP(value string) (net.IP, error) {
+    value = strings.TrimSpace(value)
+    if value == "" {
+        return nil, nil
+    }
+    interfaces, err := List()
+    if err != nil {
+        return nil, err
+    }
+    if ip := net.ParseIP(value).To4(); ip != nil {
+        for _, iface := range interfaces {
+            for _, address := range iface.Addresses {
+                if address.Equal(ip) {
+                    return ip, nil
+                }
+            }
+        }
+        return nil, fmt.Errorf("l'adresse %s n'appartient à aucune interface active", value)
+    }
+    for _, iface := range interfaces {
+        if iface.Name == value {
+            return iface.Addresses[0], nil
+        }
+    }
+    return nil, fmt.Errorf("interface réseau '%s' introuvable ou sans adresse IPv4", value)
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+    "fmt"
+    "guitarHetic/internal/config"
+    domainOutput "guitarHetic/internal/domain/output"
+    "guitarHetic/internal/infrastructure/netif"
+    "log"
+    "net"
+)
+
+// Dialer ouvre une socket UDP par cible, depuis LocalIP si elle est fixée.
+type Dialer struct {
+    LocalIP        net.IP
+    AllowBroadcast bool
+}
+
+func NewDialer(settings config.Settings) (Dialer, error) {
+    localIP, err := netif.ResolveLocalIP(settings.Network.Output)
+    if err != nil {
+        return Dialer{}, fmt.Errorf("interface de sortie: %w", err)
+    }
+    return Dialer{LocalIP: localIP, AllowBroadcast: settings.Broadcast.Enabled}, nil
+}
+
+func (d Dialer) localAddr() *net.UDPAddr {
+    if d.LocalIP == nil {
+        return nil
+    }
+    return &net.UDPAddr{IP: d.LocalIP}
+}
+
+// Dial renvoie nil, nil pour une cible de diffusion ignorée par le garde-fou.
+// Go active déjà SO_BROADCAST sur ses sockets UDP.
+func (d Dialer) Dial(target domainOutput.Target, ip net.IP, port int) (*net.UDPConn, error) {
+    if !target.Broadcast {
+        return net.DialUDP("udp4", d.localAddr(), &net.UDPAddr{IP: ip, Port: port})
+    }
+    if !d.AllowBroadcast {
+        log.Printf("Output: Diffusion désactivée dans les réglages, cible %s ignorée.", target.Address)
+        return nil, nil
+    }
+
+    broadcast := DirectedBroadcast(ip)
+    conn, err := net.DialUDP("udp4", d.localAddr(), &net.UDPAddr{IP: broadcast, Port: port})
+    if err != nil {
+        return nil, fmt.Errorf("impossible d'ouvrir la diffusion vers %s: %w", broadcast, err)
+    }
+    log.Printf("Output: Diffusion vers %s (cible %s).", broadcast, target.Address)
+    return conn, nil
+}
+
+// DirectedBroadcast : 2.0.0.10 sur une interface 2.x.x.x/8 donne 2.255.255.255.
+// Hors de tout sous-réseau local, ip est déjà une adresse de diffusion.
+func DirectedBroadcast(ip net.IP) net.IP {
+    ip4 := ip.To4()
+    if ip4 == nil {
+        return ip
+    }
+    if broadcast := localBroadcast(ip4); broadcast != nil {
+        return broadcast
+    }
+    return ip4
+}
+
+func IsBroadcast(ip net.IP) bool {
+    ip4 := ip.To4()
+    if ip4 == nil {
+        return false
+    }
+    return ip4.Equal(net.IPv4bcast) || ip4.Equal(localBroadcast(ip4))
+}
+
+func localBroadcast(ip4 net.IP) net.IP {
+    addrs, err := net.InterfaceAddrs()
+    if err != nil {
+        return nil
+    }
+    for _, addr := range addrs {
+        ipNet, ok := addr.(*net.IPNet)
+        if !ok || ipNet.IP.To4() == nil || ipNet.IP.IsLoopback() || !ipNet.Contains(ip4) {
+            continue
+        }
+        mask := net.IP(ipNet.Mask).To4()
+        if mask == nil {
+            mask = net.IP(ipNet.Mask[len(ipNet.Mask)-4:])
+        }
+        broadcast := make(net.IP, net.IPv4len)
+        for i := range broadcast {
+            broadcast[i] = ip4[i] | ^mask[i]
+        }
+        return broadcast
+    }
+    return nil
+}
@@ -0,0 +1,36 @@
+package output
+
+import (
+    "fmt"
+    "guitarHetic/internal/config"
+    domainOutput "guitarHetic/internal/domain/output"
+    "sync"
+)
+
+type Factory func(settings config.Settings) (domainOutput.Output, error)
+
+var (
+    registryMu sync.RWMutex
+    factories  = make(map[domainOutput.Protocol]Factory)
+)
+
+// Register est appelé par chaque paquet de transport dans son init.
+func Register(spec domainOutput.ProtocolSpec, factory Factory) {
+    registryMu.Lock()
+    defer registryMu.Unlock()
+    if _, exists := factories[spec.Name]; exists {
+        panic(fmt.Sprintf("output: transport %s déjà enregistré", spec.Name))
+    }
+    domainOutput.RegisterProtocol(spec)
+    factories[spec.Name] = factory
+}
+
+func New(protocol domainOutput.Protocol, settings config.Settings) (domainOutput.Output, error) {
+    registryMu.RLock()
+    factory, ok := factories[protocol]
+    registryMu.RUnlock()
+    if !ok {
+        return nil, fmt.Errorf("aucun transport enregistré pour le protocole %s", protocol)
+    }
+    return factory(settings)
+}
@@ -0,0 +1,119 @@
+package output
+
+import (
+    "context"
+    "fmt"
+    "guitarHetic/internal/config"
+    domainOutput "guitarHetic/internal/domain/output"
+    "log"
+    "sort"
+    "time"
+)
+
+const defaultFPS = 30
+
+// Sender écrit à chaque tick la dernière trame reçue de chaque univers.
+type Sender struct {
+    outputs map[domainOutput.Protocol]domainOutput.Output
+    routes  map[domainOutput.UniverseKey]domainOutput.Output
+    ticker  *time.Ticker
+}
+
+func NewSender(cfg *config.Config, settings config.Settings, fps int) (*Sender, error) {
+    if fps <= 0 {
+        fps = defaultFPS
+    }
+    s := &Sender{
+        outputs: make(map[domainOutput.Protocol]domainOutput.Output),
+        routes:  make(map[domainOutput.UniverseKey]domainOutput.Output),
+        ticker:  time.NewTicker(time.Second / time.Duration(fps)),
+    }
+
+    for protocol, destinations := range cfg.Destinations() {
+        out, err := New(protocol, settings)
+        if err != nil {
+            s.Close()
+            return nil, err
+        }
+        if err := out.Open(destinations); err != nil {
+            s.Close()
+            return nil, fmt.Errorf("ouverture de la sortie %s impossible: %w", protocol, err)
+        }
+        s.outputs[protocol] = out
+        for _, destination := range destinations {
+            s.routes[domainOutput.UniverseKey{Protocol: protocol, Universe: destination.Universe}] = out
+        }
+    }
+    log.Printf("Output Sender: %d univers répartis sur %d sortie(s) à %d FPS.", len(s.routes), len(s.outputs), fps)
+    return s, nil
+}
+
+func (s *Sender) Run(ctx context.Context, in <-chan domainOutput.Frame) {
+    log.Println("Output Sender: Démarrage de la goroutine d'envoi (TICKER + LAST FRAMES).")
+
+    latestFrames := make(map[domainOutput.UniverseKey]*[512]byte)
+    written := make(map[domainOutput.Output]bool)
+
+    for {
+        select {
+        case <-ctx.Done():
+            s.Close()
+            log.Println("Output Sender: Goroutine d'envoi terminée.")
+            return
+
+        case frame := <-in:
+            key := frame.Key()
+            if _, ok := latestFrames[key]; !ok {
+                latestFrames[key] = new([512]byte)
+            }
+            *latestFrames[key] = frame.Data
+
+        case <-s.ticker.C:
+            clear(written)
+            for key, data := range latestFrames {
+                out, ok := s.routes[key]
+                if !ok {
+                    continue
+                }
+                if err := out.WriteFrame(key.Universe, data[:]); err != nil {
+                    log.Printf("Output Sender: Erreur envoi univers %s: %v", key, err)
+                }
+                written[out] = true
+            }
+
+            for out := range written {
+                if syncer, ok := out.(domainOutput.Syncer); ok {
+                    if err := syncer.Sync(); err != nil {
+                        log.Printf("Output Sender: Erreur de synchronisation: %v", err)
+                    }
+                }
+            }
+        }
+    }
+}
+
+func (s *Sender) Stats() map[domainOutput.Protocol]domainOutput.Stats {
+    stats := make(map[domainOutput.Protocol]domainOutput.Stats, len(s.outputs))
+    for protocol, out := range s.outputs {
+        stats[protocol] = out.Stats()
+    }
+    return stats
+}
+
+func (s *Sender) Close() {
+    s.ticker.Stop()
+    protocols := make([]domainOutput.Protocol, 0, len(s.outputs))
+    for protocol := range s.outputs {
+        protocols = append(protocols, protocol)
+    }
+    sort.Slice(protocols, func(i, j int) bool { return protocols[i] < protocols[j] })
+    for _, protocol := range protocols {
+        out := s.outputs[protocol]
+        if err := out.Close(); err != nil {
+            log.Printf("Output Sender: Erreur fermeture sortie %s: %v", protocol, err)
+        }
+        stats := out.Stats()
+        log.Printf("Output Sender: Sortie %s fermée (%d trames, %d octets, %d erreurs).", protocol, stats.Frames, stats.Bytes, stats.Errors)
+    }
+    s.outputs = nil
+}
@@ -0,0 +1,139 @@
+package sacn
+
+import (
+    "errors"
+    "fmt"
+    "guitarHetic/internal/config"
+    domainOutput "guitarHetic/internal/domain/output"
+    domainSacn "guitarHetic/internal/domain/sacn"
+    infraOutput "guitarHetic/internal/infrastructure/output"
+    "log"
+    "net"
+    "os"
+)
+
+func init() {
+    infraOutput.Register(domainOutput.ProtocolSpec{
+        Name:          domainSacn.Protocol,
+        Aliases:       []string{"E1.31", "E131"},
+        ParseUniverse: domainSacn.ParseUniverse,
+        LastUniverse:  domainSacn.MaxUniverse,
+        Multicast:     true,
+    }, NewOutput)
+}
+
+// Output émet des paquets de données E1.31, en unicast ou vers le groupe
+// multicast de chaque univers.
+type Output struct {
+    cid          [16]byte
+    sourceName   string
+    priority     byte
+    dialer       infraOutput.Dialer
+    conns        map[int][]*net.UDPConn
+    headerCache  map[int][]byte
+    frameLengths map[int]int
+    sequences    map[int]byte
+    counters     domainOutput.Counters
+}
+
+func NewOutput(settings config.Settings) (domainOutput.Output, error) {
+    cid, err := resolveCID(settings.SACN.CID)
+    if err != nil {
+        return nil, err
+    }
+    priority := settings.SACN.Priority
+    if priority < 0 || priority > domainSacn.MaxPriority {
+        return nil, fmt.Errorf("priorité sACN hors plage (0-%d): %d", domainSacn.MaxPriority, priority)
+    }
+    dialer, err := infraOutput.NewDialer(settings)
+    if err != nil {
+        return nil, err
+    }
+    return &Output{
+        cid:          cid,
+        sourceName:   settings.SACN.SourceName,
+        priority:     byte(priority),
+        dialer:       dialer,
+        conns:        make(map[int][]*net.UDPConn),
+        headerCache:  make(map[int][]byte),
+        frameLengths: make(map[int]int),
+        sequences:    make(map[int]byte),
+    }, nil
+}
+
+// resolveCID dérive un CID du nom de la machine quand les réglages n'en fixent pas.
+func resolveCID(value string) ([16]byte, error) {
+    if value != "" {
+        return domainSacn.ParseCID(value)
+    }
+    hostname, err := os.Hostname()
+    if err != nil {
+        hostname = "guitarHetic"
+    }
+    return domainSacn.DeriveCID(hostname), nil
+}
+
+func (o *Output) Open(destinations []domainOutput.Destination) error {
+    log.Println("sACN Output: Initialisation et pré-calcul des en-têtes...")
+    for _, d := range destinations {
+        o.frameLengths[d.Universe] = d.FrameLength
+        o.headerCache[d.Universe] = domainSacn.BuildDataHeader(o.cid, o.sourceName, o.priority, d.Universe, d.FrameLength)
+
+        o.conns[d.Universe] = nil
+        for _, target := range d.Targets {
+            ip := net.ParseIP(target.Address)
+            if target.Address == config.MulticastIP {
+                ip = domainSacn.MulticastAddress(d.Universe)
+            }
+            conn, err := o.dialer.Dial(target, ip, domainSacn.Port)
+            if err != nil {
+                o.Close()
+                return err
+            }
+            if conn != nil {
+                o.conns[d.Universe] = append(o.conns[d.Universe], conn)
+            }
+        }
+    }
+    log.Printf("sACN Output: Initialisé pour %d univers (source '%s', priorité %d).", len(o.conns), o.sourceName, o.priority)
+    return nil
+}
+
+func (o *Output) WriteFrame(universe int, data []byte) error {
+    conns, ok := o.conns[universe]
+    if !ok {
+        return fmt.Errorf("univers %d non ouvert en sACN", universe)
+    }
+
+    length := o.frameLengths[universe]
+    packet := make([]byte, domainSacn.HeaderSize+length)
+    copy(packet, o.headerCache[universe])
+    copy(packet[domainSacn.HeaderSize:], data[:min(length, len(data))])
+
+    domainSacn.SetSequence(packet, o.sequences[universe])
+    o.sequences[universe]++
+
+    var errs []error
+    for _, conn := range conns {
+        _, err := conn.Write(packet)
+        o.counters.Record(len(packet), err)
+        if err != nil {
+            errs = append(errs, err)
+        }
+    }
+    return errors.Join(errs...)
+}
+
+func (o *Output) Stats() domainOutput.Stats {
+    return o.counters.Snapshot()
+}
+
+func (o *Output) Close() error {
+    for _, conns := range o.conns {
+        for _, conn := range conns {
+            conn.Close()
+        }
+    }
+    log.Println("sACN Output: Connexions UDP fermées.")
+    return nil
+}
@@ -0,0 +1,53 @@
+package pipeline
+
+import (
+    "flag"
+    "guitarHetic/internal/config"
+    "log"
+)
+
+const DefaultFPS = 30
+
+// CommandLine regroupe les options communes à l'interface graphique et au
+// routeur headless.
+type CommandLine struct {
+    SettingsPath string
+    EHubPort     int
+    FPS          int
+    Settings     config.Settings
+    fileEHubPort int
+}
+
+// RegisterFlags déclare -port, -fps et -settings sur fs ; LoadSettings lit
+// ensuite le fichier de réglages, une fois les options analysées.
+func (c *CommandLine) RegisterFlags(fs *flag.FlagSet) {
+    fs.IntVar(&c.EHubPort, "port", 0, "Port UDP d'écoute eHub (remplace celui du fichier de réglages)")
+    fs.IntVar(&c.FPS, "fps", DefaultFPS, "Cadence d'envoi Art-Net en images par seconde")
+    fs.StringVar(&c.SettingsPath, "settings", config.DefaultSettingsPath(), "Fichier de réglages (JSON)")
+}
+
+func (c *CommandLine) LoadSettings() {
+    settings, err := config.LoadSettings(c.SettingsPath)
+    if err != nil {
+        log.Printf("ERREUR: %v. Utilisation des réglages par défaut.", err)
+    }
+    c.fileEHubPort = settings.EHub.Port
+    if c.EHubPort != 0 {
+        settings.EHub.Port = c.EHubPort
+    }
+    c.Settings = settings
+}
+
+// PersistedSettings renvoie les réglages à enregistrer : une surcharge passée
+// en ligne de commande ne remplace pas la valeur du fichier.
+func (c CommandLine) PersistedSettings() config.Settings {
+    settings := c.Settings
+    if c.EHubPort != 0 {
+        settings.EHub.Port = c.fileEHubPort
+    }
+    return settings
+}
+
+func (c CommandLine) Pipeline() Options {
+    return Options{FPS: c.FPS, Settings: c.Settings}
+}
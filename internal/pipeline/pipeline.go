@@ -0,0 +1,161 @@
+// Package pipeline assemble le routeur, de la réception eHub à l'envoi des
+// trames, pour l'exécutable graphique comme pour cmd/router.
+package pipeline
+
+import (
+    "context"
+    "fmt"
+    app_ehub "guitarHetic/internal/application/ehub"
+    app_processor "guitarHetic/internal/application/processor"
+    "guitarHetic/internal/config"
+    domain_artnet "guitarHetic/internal/domain/artnet"
+    "guitarHetic/internal/domain/ehub"
+    "guitarHetic/internal/domain/monitor"
+    "guitarHetic/internal/domain/output"
+    infra_output "guitarHetic/internal/infrastructure/output"
+    "guitarHetic/internal/simulator"
+    "log"
+    "time"
+)
+
+// Faker sert de motif de secours à la perte d'entrée ; OnInputLoss, si
+// fourni, est prévenu de la perte (lost à true) puis du retour de l'entrée.
+type Options struct {
+    FPS         int
+    Settings    config.Settings
+    Faker       *simulator.Faker
+    OnInputLoss func(lost bool, message string)
+}
+
+// Start lit les paquets eHub de rawPackets, alimenté par un
+// listener qui survit aux redémarrages du pipeline. Rien n'est lancé si les
+// réglages sont invalides ou si les sorties ne s'ouvrent pas.
+func Start(ctx context.Context, cfg *config.Config, monitorPublisher monitor.Publisher, rawPackets <-chan ehub.RawPacket, eHubUpdateOut, fakerUpdateOut chan *ehub.EHubUpdateMsg, fakerConfigOut chan *ehub.EHubConfigMsg, fakerModeSwitch chan bool, opts Options) (*app_processor.Service, <-chan struct{}, error) {
+    log.Println("Pipeline: Démarrage des services...")
+
+    eHubConfigOut := make(chan *ehub.EHubConfigMsg, 50)
+    frameQueue := make(chan output.Frame, 10000)
+    finalConfigIn := make(chan *ehub.EHubConfigMsg, 50)
+    finalUpdateIn := make(chan *ehub.EHubUpdateMsg, 1000)
+
+    merge, err := app_processor.NewMerge(opts.Settings.Merge)
+    if err != nil {
+        return nil, nil, fmt.Errorf("réglages de fusion eHub invalides: %w", err)
+    }
+    log.Printf("Pipeline: Fusion des émetteurs eHub en %s (délai %s).", merge.Policy, merge.Timeout)
+    inputLoss, err := app_processor.NewInputLoss(opts.Settings.InputLoss)
+    if err != nil {
+        return nil, nil, fmt.Errorf("réglages de perte d'entrée invalides: %w", err)
+    }
+    if inputLoss.Action == app_processor.LossPattern && (opts.Faker == nil || !simulator.IsPattern(inputLoss.Pattern)) {
+        log.Printf("AVERTISSEMENT: Motif de secours '%s' indisponible, la dernière trame sera maintenue.", inputLoss.Pattern)
+        inputLoss.Action = app_processor.LossHold
+    }
+    parser := app_ehub.NewParser()
+    eHubService := app_ehub.NewService(rawPackets, parser, eHubConfigOut, eHubUpdateOut)
+    processorService, physicalConfigOut := app_processor.NewService(finalConfigIn, finalUpdateIn, frameQueue, monitorPublisher, merge)
+
+    sender, err := infra_output.NewSender(cfg, opts.Settings, opts.FPS)
+    if err != nil {
+        return nil, nil, fmt.Errorf("impossible d'initialiser les sorties: %w", err)
+    }
+
+    notifyInputLoss := func(lost bool, message string) {
+        log.Printf("Aiguilleur: %s", message)
+        if opts.OnInputLoss != nil {
+            opts.OnInputLoss(lost, message)
+        }
+    }
+
+    go func() {
+        isFakerActive := false
+        log.Println("Aiguilleur: Démarré en mode LIVE.")
+
+        // La perte d'entrée n'est surveillée qu'en mode LIVE, après une
+        // première mise à jour eHub ; lossFaker note un Faker lancé en secours.
+        var lastLiveUpdate time.Time
+        inputLost, lossFaker := false, false
+        lossTicker := time.NewTicker(100 * time.Millisecond)
+        defer lossTicker.Stop()
+
+        for {
+            select {
+            case <-ctx.Done():
+                log.Println("Aiguilleur: Arrêt.")
+                return
+            case mode := <-fakerModeSwitch:
+                if !mode {
+                    lossFaker = false
+                }
+                if mode != isFakerActive {
+                    isFakerActive = mode
+                    if isFakerActive {
+                        log.Println("Aiguilleur: Passage en mode FAKER.")
+                    } else {
+                        log.Println("Aiguilleur: Retour au mode LIVE.")
+                    }
+                }
+            case <-lossTicker.C:
+                if inputLoss.Timeout == 0 || inputLost || isFakerActive || lastLiveUpdate.IsZero() || time.Since(lastLiveUpdate) < inputLoss.Timeout {
+                    continue
+                }
+                inputLost = true
+                notifyInputLoss(true, fmt.Sprintf("Entrée eHub perdue depuis %s (aucune mise à jour depuis %s), %s.",
+                    lastLiveUpdate.Format("15:04:05"), inputLoss.Timeout, inputLoss.Action))
+                switch inputLoss.Action {
+                case app_processor.LossFade:
+                    processorService.FadeOut(ctx, inputLoss.Fade)
+                case app_processor.LossPattern:
+                    // Le Faker signale son passage en mode FAKER à cette même boucle.
+                    lossFaker = true
+                    go opts.Faker.SendTestPattern(inputLoss.Pattern)
+                }
+            case msg := <-fakerUpdateOut:
+                if isFakerActive {
+                    finalUpdateIn <- msg
+                }
+            case msg := <-eHubUpdateOut:
+                lastLiveUpdate = time.Now()
+                if inputLost {
+                    inputLost = false
+                    if lossFaker {
+                        lossFaker, isFakerActive = false, false
+                        go opts.Faker.SwitchToLiveMode()
+                    }
+                    notifyInputLoss(false, fmt.Sprintf("Entrée eHub rétablie à %s.", lastLiveUpdate.Format("15:04:05")))
+                }
+                if !isFakerActive {
+                    finalUpdateIn <- msg
+                }
+            case msg := <-fakerConfigOut:
+                if isFakerActive {
+                    finalConfigIn <- msg
+                }
+            case msg := <-eHubConfigOut:
+                if !isFakerActive {
+                    finalConfigIn <- msg
+                }
+            }
+        }
+    }()
+
+    eHubService.Start(ctx)
+    processorService.Start(ctx)
+    senderDone := make(chan struct{})
+    go func() {
+        sender.Run(ctx, frameQueue)
+        close(senderDone)
+    }()
+
+    physicalConfigOut <- cfg
+
+    return processorService, senderDone, nil
+}
+
+// ConfiguredUniverses liste les univers Art-Net émis par cfg, annoncés en réponse aux ArtPoll.
+func ConfiguredUniverses(cfg *config.Config) []int {
+    if cfg == nil {
+        return nil
+    }
+    return cfg.UniversesFor(domain_artnet.Protocol)
+}
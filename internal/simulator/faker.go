@@ -13,46 +13,63 @@ import (
 )
 
 type Faker struct {
-    updateOut    chan<- *ehub.EHubUpdateMsg
-    configOut    chan<- *ehub.EHubConfigMsg
-    modeSwitch   chan<- bool
-    config       *config.Config
-    allEntityIDs []uint16
+    updateOut  chan<- *ehub.EHubUpdateMsg
+    configOut  chan<- *ehub.EHubConfigMsg
+    modeSwitch chan<- bool
+    config     *config.Config
+    entityIDs  map[int][]uint16
 
     mu              sync.Mutex
     cancelAnimation context.CancelFunc
 }
 
+// NewFaker regroupe les entités par univers eHub : chaque univers reçoit sa
+// propre configuration et ses propres mises à jour, comme depuis Tan.
 func NewFaker(updateOut chan<- *ehub.EHubUpdateMsg, configOut chan<- *ehub.EHubConfigMsg, modeSwitch chan<- bool, cfg *config.Config) *Faker {
-    var entityIDs []uint16
+    entityIDs := make(map[int][]uint16)
 
     if cfg != nil {
         for _, entry := range cfg.RoutingTable {
-            entityIDs = append(entityIDs, uint16(entry.EntityID))
+            entityIDs[entry.EHubUniverse] = append(entityIDs[entry.EHubUniverse], uint16(entry.EntityID))
         }
-        slices.Sort(entityIDs)
-        log.Printf("Faker: Initialisé avec %d entités.", len(entityIDs))
+        for universe, ids := range entityIDs {
+            slices.Sort(ids)
+            entityIDs[universe] = slices.Compact(ids)
+        }
+        log.Printf("Faker: Initialisé avec %d entités sur %d univers eHub.", len(cfg.RoutingTable), len(entityIDs))
     } else {
         log.Println("Faker: Initialisé sans configuration (en attente de chargement).")
     }
 
     return &Faker{
-        updateOut:    updateOut,
-        configOut:    configOut,
-        modeSwitch:   modeSwitch,
-        config:       cfg,
-        allEntityIDs: entityIDs,
+        updateOut:  updateOut,
+        configOut:  configOut,
+        modeSwitch: modeSwitch,
+        config:     cfg,
+        entityIDs:  entityIDs,
     }
 }
 
-func (f *Faker) sendStaticPattern(entities []ehub.EHubEntityState) {
+func (f *Faker) sendStaticPattern(entities func(ids []uint16) []ehub.EHubEntityState) {
     f.modeSwitch <- true
     f.sendInitialConfig()
-    updateMsg := &ehub.EHubUpdateMsg{
-        Universe: 0,
-        Entities: entities,
+    for universe, ids := range f.entityIDs {
+        updateMsg := &ehub.EHubUpdateMsg{
+            Universe: universe,
+            Entities: entities(ids),
+        }
+        f.updateOut <- updateMsg
+    }
+}
+
+// IsPattern indique si name est un motif accepté par SendTestPattern sans
+// paramètre de couleur.
+func IsPattern(name string) bool {
+    switch name {
+    case "white", "red", "green", "blue", "black", "off", "animation", "stop":
+        return true
     }
-    f.updateOut <- updateMsg
+    return false
 }
 
 func (f *Faker) SendTestPattern(command string, color ...byte) {
@@ -88,11 +105,13 @@ func (f *Faker) SendTestPattern(command string, color ...byte) {
 }
 
 func (f *Faker) sendSolidColor(r, g, b, w byte) {
-    entities := make([]ehub.EHubEntityState, len(f.allEntityIDs))
-    for i, entityID := range f.allEntityIDs {
-        entities[i] = ehub.EHubEntityState{ID: entityID, Red: r, Green: g, Blue: b, White: w}
-    }
-    f.sendStaticPattern(entities)
+    f.sendStaticPattern(func(ids []uint16) []ehub.EHubEntityState {
+        entities := make([]ehub.EHubEntityState, len(ids))
+        for i := range ids {
+            entities[i] = ehub.EHubEntityState{ID: uint16(i), Red: r, Green: g, Blue: b, White: w}
+        }
+        return entities
+    })
 }
 
 func (f *Faker) StartWaveAnimation() {
@@ -131,8 +150,10 @@ func (f *Faker) StartWaveAnimation() {
                 if position > 1.0 {
                     position = 0.0
                 }
-                entities := f.calculateWaveFrame(position, 0.3, 255, 100, 0)
-                f.updateOut <- &ehub.EHubUpdateMsg{Universe: 0, Entities: entities}
+                for universe, ids := range f.entityIDs {
+                    entities := calculateWaveFrame(ids, position, 0.3, 255, 100, 0)
+                    f.updateOut <- &ehub.EHubUpdateMsg{Universe: universe, Entities: entities}
+                }
 
             case <-ctx.Done():
                 return
@@ -161,26 +182,32 @@ func (f *Faker) SwitchToLiveMode() {
     f.modeSwitch <- false
 }
 
+// sendInitialConfig numérote les sextuors dans l'ordre des entités, sans
+// trou : chaque suite d'identifiants consécutifs devient une plage.
 func (f *Faker) sendInitialConfig() {
-    if len(f.allEntityIDs) == 0 {
-        return
-    }
-    configMsg := &ehub.EHubConfigMsg{
-        Universe: 0,
-        Ranges: []ehub.EHubConfigRange{{
-            SextuorStart: 0,
-            EntityStart:  f.allEntityIDs[0],
-            SextuorEnd:   uint16(len(f.allEntityIDs) - 1),
-            EntityEnd:    f.allEntityIDs[len(f.allEntityIDs)-1],
-        }},
+    for universe, ids := range f.entityIDs {
+        var ranges []ehub.EHubConfigRange
+        start := 0
+        for i := 1; i <= len(ids); i++ {
+            if i < len(ids) && ids[i] == ids[i-1]+1 {
+                continue
+            }
+            ranges = append(ranges, ehub.EHubConfigRange{
+                SextuorStart: uint16(start),
+                EntityStart:  ids[start],
+                SextuorEnd:   uint16(i - 1),
+                EntityEnd:    ids[i-1],
+            })
+            start = i
+        }
+        f.configOut <- &ehub.EHubConfigMsg{Universe: universe, Ranges: ranges}
     }
-    f.configOut <- configMsg
 }
 
-func (f *Faker) calculateWaveFrame(position, width float64, r, g, b byte) []ehub.EHubEntityState {
-    entities := make([]ehub.EHubEntityState, len(f.allEntityIDs))
-    totalEntities := len(f.allEntityIDs)
-    for i, entityID := range f.allEntityIDs {
+func calculateWaveFrame(ids []uint16, position, width float64, r, g, b byte) []ehub.EHubEntityState {
+    entities := make([]ehub.EHubEntityState, len(ids))
+    totalEntities := len(ids)
+    for i := range ids {
         entityPos := float64(i) / float64(totalEntities-1)
         distance := math.Abs(entityPos - position)
         var intensity float64
@@ -188,7 +215,7 @@ func (f *Faker) calculateWaveFrame(position, width float64, r, g, b byte) []ehub
             intensity = 1.0 - (distance / (width / 2))
         }
         entities[i] = ehub.EHubEntityState{
-            ID:    entityID,
+            ID:    uint16(i),
             Red:   byte(float64(r) * intensity),
             Green: byte(float64(g) * intensity),
             Blue:  byte(float64(b) * intensity),
@@ -16,6 +16,8 @@ func RunUI(
     controller *UIController,
     w fyne.Window,
 ) {
+    controller.window = w
+    controller.statusBar = widget.NewLabel("")
     mainMenu := buildMainMenu(controller, w)
     w.SetMainMenu(mainMenu)
 
@@ -40,7 +42,7 @@ func RunUI(
         }
 
         header := buildHeader(controller.state, controller)
-        fullContent := container.NewBorder(header, nil, nil, nil, viewContent)
+        fullContent := container.NewBorder(header, controller.statusBar, nil, nil, viewContent)
         w.SetContent(fullContent)
     }
 
@@ -52,6 +54,7 @@ func RunUI(
 
 func buildMainMenu(controller *UIController, parentWindow fyne.Window) *fyne.MainMenu {
     xlsxFilter := storage.NewExtensionFileFilter([]string{".xlsx"})
+    routingFilter := storage.NewExtensionFileFilter([]string{".xlsx", ".csv"})
 
     fileMenu := fyne.NewMenu("Art'hetic",
         fyne.NewMenuItem("Charger configuration...", func() {
@@ -65,7 +68,7 @@ func buildMainMenu(controller *UIController, parentWindow fyne.Window) *fyne.Mai
             if controller.state.lastOpenedFolder != nil {
                 fileDialog.SetLocation(controller.state.lastOpenedFolder)
             }
-            fileDialog.SetFilter(xlsxFilter)
+            fileDialog.SetFilter(routingFilter)
             fileDialog.Show()
         }),
         fyne.NewMenuItem("Sauvegarder la configuration sous...", func() {
@@ -77,9 +80,31 @@ func buildMainMenu(controller *UIController, parentWindow fyne.Window) *fyne.Mai
                 writer.Close()
             }, parentWindow)
             fileDialog.SetFileName("routing_export.xlsx")
-            fileDialog.SetFilter(xlsxFilter)
+            fileDialog.SetFilter(routingFilter)
             fileDialog.Show()
         }),
+        fyne.NewMenuItem("Découvrir les nœuds Art-Net (ArtPoll)", func() {
+            controller.DiscoverNodes()
+        }),
+        fyne.NewMenuItem("Interfaces réseau...", func() {
+            controller.ShowNetworkInterfaces()
+        }),
+        fyne.NewMenuItem("Monitorer un univers...", func() {
+            universeInput := NewSizedEntry(200.0)
+            universeInput.SetPlaceHolder("ex. 17 ou 0.1.1")
+            content := container.NewVBox(
+                widget.NewLabel("Univers (forme plate ou net.subnet.universe) :"),
+                universeInput,
+            )
+            dialog.ShowCustomConfirm("Monitorer un univers", "Monitorer", "Annuler", content, func(ok bool) {
+                if !ok {
+                    return
+                }
+                if err := controller.MonitorUniverse(universeInput.Text); err != nil {
+                    dialog.ShowError(err, parentWindow)
+                }
+            }, parentWindow)
+        }),
         fyne.NewMenuItem("Quitter", func() {
             controller.QuitApp()
         }),
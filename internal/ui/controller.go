@@ -3,8 +3,12 @@ package ui
 import (
     "fmt"
     "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/dialog"
     "fyne.io/fyne/v2/storage"
+    "fyne.io/fyne/v2/widget"
     "guitarHetic/internal/config"
+    "guitarHetic/internal/domain/monitor"
+    "guitarHetic/internal/domain/output"
     "guitarHetic/internal/simulator"
     "image/color"
     "log"
@@ -18,13 +22,19 @@ type UIController struct {
     state           *UIState
     onStateChange   func()
     app             fyne.App
+    window          fyne.Window
     faker           *simulator.Faker
-    monitorIn       <-chan *UniverseMonitorData
+    monitorIn       <-chan *monitor.UniverseMonitorData
     configRequester ConfigRequester
+    nodeDiscoverer  NodeDiscoverer
+    interfaceLister InterfaceLister
+    network         config.NetworkSettings
+    eHub            config.EHubSettings
+    statusBar       *widget.Label
     isConfigLoaded  bool
 }
 
-func NewUIController(app fyne.App, faker *simulator.Faker, monitorIn <-chan *UniverseMonitorData, configRequester ConfigRequester) *UIController {
+func NewUIController(app fyne.App, faker *simulator.Faker, monitorIn <-chan *monitor.UniverseMonitorData, configRequester ConfigRequester) *UIController {
     c := &UIController{
         state:           NewUIState(nil),
         onStateChange:   func() {},
@@ -57,6 +67,101 @@ func (c *UIController) SetFaker(newFaker *simulator.Faker) {
     c.faker = newFaker
 }
 
+func (c *UIController) SetNodeDiscoverer(discoverer NodeDiscoverer) {
+    c.nodeDiscoverer = discoverer
+}
+
+func (c *UIController) DiscoverNodes() {
+    if c.nodeDiscoverer == nil {
+        return
+    }
+    log.Println("UI Controller: Lancement de la découverte ArtPoll.")
+    go func() {
+        nodes, err := c.nodeDiscoverer()
+        fyne.Do(func() {
+            if err != nil {
+                log.Printf("UI ERROR: Découverte Art-Net impossible: %v", err)
+                dialog.ShowError(err, c.window)
+                return
+            }
+            c.state.discoveredNodes = nodes
+            c.state.discoveryDone = true
+            c.onStateChange()
+            dialog.ShowCustom("Nœuds Art-Net découverts", "Fermer", buildNodeListContent(c.state), c.window)
+        })
+    }()
+}
+
+// SetInterfaceLister fournit la liste des interfaces et le choix actuel des réglages.
+func (c *UIController) SetInterfaceLister(lister InterfaceLister, network config.NetworkSettings, eHub config.EHubSettings) {
+    c.interfaceLister = lister
+    c.network = network
+    c.eHub = eHub
+}
+
+func (c *UIController) ShowNetworkInterfaces() {
+    if c.interfaceLister == nil {
+        return
+    }
+    interfaces, err := c.interfaceLister()
+    if err != nil {
+        log.Printf("UI ERROR: %v", err)
+        dialog.ShowError(err, c.window)
+        return
+    }
+    content, selection := buildNetworkContent(interfaces, c.network, c.eHub)
+    d := dialog.NewCustomConfirm("Interfaces réseau", "Appliquer", "Annuler", content, func(ok bool) {
+        if !ok {
+            return
+        }
+        network, eHub, err := selection()
+        if err != nil {
+            dialog.ShowError(err, c.window)
+            return
+        }
+        c.SetNetwork(network, eHub)
+    }, c.window)
+    d.Resize(fyne.NewSize(600, 400))
+    d.Show()
+}
+
+// SetNetwork ne transmet que les réglages modifiés.
+func (c *UIController) SetNetwork(network config.NetworkSettings, eHub config.EHubSettings) {
+    var req ConfigUpdateRequest
+    if network != c.network {
+        log.Printf("UI Controller: Demande de changement d'interface de sortie ('%s')", network.Output)
+        c.network = network
+        req.Network = &network
+    }
+    if eHub != c.eHub {
+        log.Printf("UI Controller: Demande de changement d'écoute eHub ('%s', port %d)", eHub.Address, eHub.Port)
+        c.eHub = eHub
+        req.EHub = &eHub
+    }
+    if req.Network == nil && req.EHub == nil {
+        return
+    }
+    c.configRequester(req)
+}
+
+// SetStatus affiche message dans la barre d'état, en gras pour une alerte.
+func (c *UIController) SetStatus(message string, alert bool) {
+    fyne.Do(func() {
+        if c.statusBar == nil {
+            return
+        }
+        c.statusBar.TextStyle = fyne.TextStyle{Bold: alert}
+        c.statusBar.SetText(message)
+    })
+}
+
+// ShowError affiche une erreur survenue hors du fil de l'interface.
+func (c *UIController) ShowError(err error) {
+    fyne.Do(func() {
+        dialog.ShowError(err, c.window)
+    })
+}
+
 func (c *UIController) IsConfigLoaded() bool {
     return c.isConfigLoaded
 }
@@ -69,6 +174,7 @@ func (c *UIController) UpdateWithNewConfig(cfg *config.Config) {
         } else {
             newIPs, newCtrlMap := BuildModel(cfg)
             c.state.allControllers = newCtrlMap
+            c.state.universeTargets = cfg.UniverseTargets
             c.state.controllerIPs = newIPs
             c.state.viewStack = make([]ViewName, 0)
             c.state.CurrentView = IPListView
@@ -78,6 +184,15 @@ func (c *UIController) UpdateWithNewConfig(cfg *config.Config) {
     })
 }
 
+func (c *UIController) ShowLoadReport(report *config.Report, onClosed func()) {
+    fyne.Do(func() {
+        d := dialog.NewCustom("Rapport de chargement", "Continuer", buildLoadReportContent(report), c.window)
+        d.SetOnClosed(onClosed)
+        d.Resize(fyne.NewSize(800, 450))
+        d.Show()
+    })
+}
+
 func (c *UIController) LoadNewConfigFile(uri fyne.URI) {
     log.Printf("UI Controller: Demande de chargement du fichier: %s", uri.Path())
 
@@ -106,17 +221,19 @@ func (c *UIController) ValidateNewIP(newIPStr string) {
     c.configRequester(ConfigUpdateRequest{IPChanges: ipChanges})
 }
 
-func (c *UIController) ValidateNewIPForUniverse(universeID int, newIPStr string) {
-    if net.ParseIP(newIPStr) == nil {
-        log.Printf("UI ERROR: L'adresse IP '%s' pour l'univers %d est invalide. Abandon.", newIPStr, universeID)
+// ValidateNewIPForUniverse accepte une liste de destinations : le contrôleur
+// puis les miroirs, avec le préfixe "broadcast:" pour une adresse de diffusion.
+func (c *UIController) ValidateNewIPForUniverse(universe output.UniverseKey, newIPStr string) {
+    if _, err := config.ParseTargets(newIPStr); err != nil {
+        log.Printf("UI ERROR: Destinations '%s' pour l'univers %s invalides: %v. Abandon.", newIPStr, universe, err)
         return
     }
 
-    log.Printf("UI Controller: Demande de changement d'IP pour l'univers %d vers '%s'", universeID, newIPStr)
+    log.Printf("UI Controller: Demande de changement des destinations de l'univers %s vers '%s'", universe, newIPStr)
 
     ipChanges := make(map[string]string)
 
-    universeKey := fmt.Sprintf("universe:%d", universeID)
+    universeKey := fmt.Sprintf("universe:%s:%d", universe.Protocol, universe.Universe)
     ipChanges[universeKey] = newIPStr
 
     c.configRequester(ConfigUpdateRequest{IPChanges: ipChanges})
@@ -155,24 +272,59 @@ func (c *UIController) GoBack() {
     c.onStateChange()
 }
 
-func (c *UIController) SelectUniverseAndShowDetails(universeID int) {
-    entityCount := 0
-    for _, ipData := range c.state.allControllers {
-        if ranges, ok := ipData[universeID]; ok {
-            for _, r := range ranges {
-                entityCount += (r[1] - r[0] + 1)
+// MonitorUniverse ouvre l'univers saisi dans le premier protocole qui sait le
+// lire et où il est routé : Art-Net avant sACN pour un numéro valable dans les deux.
+func (c *UIController) MonitorUniverse(input string) error {
+    var parseErr error
+    var unrouted *output.UniverseKey
+    for _, protocol := range output.Protocols() {
+        spec, _ := output.LookupProtocol(protocol)
+        universe, err := spec.ParseUniverse(input)
+        if err != nil {
+            if parseErr == nil {
+                parseErr = err
             }
+            continue
+        }
+        key := output.UniverseKey{Protocol: protocol, Universe: universe}
+        if c.SelectUniverseAndShowDetails(key) {
+            return nil
+        }
+        if unrouted == nil {
+            unrouted = &key
         }
     }
+    if unrouted != nil {
+        return fmt.Errorf("aucune entité n'est routée vers l'univers %s", formatUniverse(*unrouted))
+    }
+    return parseErr
+}
+
+func (c *UIController) SelectUniverseAndShowDetails(universe output.UniverseKey) bool {
+    var ranges []EntityRange
+    for _, ipData := range c.state.allControllers {
+        ranges = append(ranges, ipData[universe]...)
+    }
+    sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+
+    slots := make([]ledSlot, 0)
+    for _, r := range ranges {
+        for id := r.Start; id <= r.End; id++ {
+            slots = append(slots, ledSlot{Offset: r.DMXStart - 1 + (id-r.Start)*r.Format.Channels(), Format: r.Format})
+        }
+    }
+    entityCount := len(slots)
 
     if entityCount == 0 {
-        log.Printf("UI CONTROLLER: Aucune entité trouvée pour l'univers %d. Affichage annulé.", universeID)
-        return
+        log.Printf("UI CONTROLLER: Aucune entité trouvée pour l'univers %s. Affichage annulé.", universe)
+        return false
     }
-    log.Printf("UI CONTROLLER: Construction de la vue pour l'univers %d avec %d entités.", universeID, entityCount)
-    c.state.selectedUniverse = universeID
+    log.Printf("UI CONTROLLER: Construction de la vue pour l'univers %s avec %d entités.", universe, entityCount)
+    c.state.selectedUniverse = universe
+    c.state.universeSlots = slots
     c.state.universeViewContent = buildUniverseView(c.state, entityCount)
     c.navigateTo(UniverseView)
+    return true
 }
 
 func (c *UIController) SelectIPAndShowDetails(ip string) {
@@ -180,9 +332,9 @@ func (c *UIController) SelectIPAndShowDetails(ip string) {
     entries := c.state.allControllers[ip]
     details := make([]UniRange, 0, len(entries))
     for u, ranges := range entries {
-        details = append(details, UniRange{Universe: u, Ranges: ranges})
+        details = append(details, UniRange{Universe: u, Ranges: ranges, Mirrors: c.state.mirrorsOf(u, ip)})
     }
-    sort.Slice(details, func(i, j int) bool { return details[i].Universe < details[j].Universe })
+    sort.Slice(details, func(i, j int) bool { return output.CompareUniverseKeys(details[i].Universe, details[j].Universe) < 0 })
     c.state.selectedDetails = details
     c.navigateTo(DetailView)
 }
@@ -214,26 +366,28 @@ func (c *UIController) SwitchToLiveMode() {
 
 func (c *UIController) listenForMonitorUpdates() {
     for data := range c.monitorIn {
-        if c.state.CurrentView != UniverseView || c.state.selectedUniverse != data.UniverseID || c.state.universeViewContent == nil {
+        if c.state.CurrentView != UniverseView || c.state.selectedUniverse != data.Universe || c.state.universeViewContent == nil {
             continue
         }
         inputColors := make([]color.Color, len(c.state.ledInputWidgets))
         for i := range inputColors {
             if i < len(data.InputState) {
                 entity := data.InputState[i]
-                inputColors[i] = amplifyColor(entity.Red, entity.Green, entity.Blue)
+                inputColors[i] = amplifyColor(entity.Red, entity.Green, entity.Blue, entity.White)
             } else {
                 inputColors[i] = color.Black
             }
         }
         outputColors := make([]color.Color, len(c.state.ledOutputWidgets))
         for i := range outputColors {
-            offset := i * 3
-            if offset+2 < len(data.OutputDMX) {
-                r, g, b := data.OutputDMX[offset], data.OutputDMX[offset+1], data.OutputDMX[offset+2]
-                outputColors[i] = amplifyColor(r, g, b)
-            } else {
-                outputColors[i] = color.Black
+            outputColors[i] = color.Black
+            if i >= len(c.state.universeSlots) {
+                continue
+            }
+            slot := c.state.universeSlots[i]
+            if slot.Offset+slot.Format.Channels() <= len(data.OutputDMX) {
+                r, g, b, w := slot.Format.Decode(data.OutputDMX[slot.Offset:])
+                outputColors[i] = amplifyColor(r, g, b, w)
             }
         }
         fyne.Do(func() {
@@ -252,8 +406,10 @@ func (c *UIController) listenForMonitorUpdates() {
     }
 }
 
-func amplifyColor(r, g, b byte) color.Color {
+func amplifyColor(r, g, b, w byte) color.Color {
     const minBrightness = 100
+    // Le blanc s'ajoute aux trois composantes pour l'affichage.
+    r, g, b = addWhite(r, w), addWhite(g, w), addWhite(b, w)
     if r == 0 && g == 0 && b == 0 {
         return color.Black
     }
@@ -268,3 +424,10 @@ func amplifyColor(r, g, b byte) color.Color {
     }
     return color.NRGBA{R: r, G: g, B: b, A: 255}
 }
+
+func addWhite(c, w byte) byte {
+    if int(c)+int(w) > 255 {
+        return 255
+    }
+    return c + w
+}
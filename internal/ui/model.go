@@ -1,63 +1,81 @@
 package ui
 
 import (
+    "fmt"
     "guitarHetic/internal/config"
+    "guitarHetic/internal/domain/artnet"
+    "guitarHetic/internal/domain/output"
+    "guitarHetic/internal/domain/pixel"
     "sort"
 )
 
+type EntityRange struct {
+    Start    int
+    End      int
+    DMXStart int
+    Format   pixel.Format
+}
+
 type UniRange struct {
-    Universe int
-    Ranges   [][2]int
+    Universe output.UniverseKey
+    Ranges   []EntityRange
+    Mirrors  []output.Target
+}
+
+func formatUniverse(key output.UniverseKey) string {
+    if key.Protocol == artnet.Protocol {
+        return fmt.Sprintf("%d (%s)", key.Universe, artnet.PortAddress(key.Universe))
+    }
+    return fmt.Sprintf("%d (%s)", key.Universe, key.Protocol)
 }
 
-func BuildModel(cfg *config.Config) ([]string, map[string]map[int][][2]int) {
-    controllers := make(map[string]map[int][][2]int)
+func BuildModel(cfg *config.Config) ([]string, map[string]map[output.UniverseKey][]EntityRange) {
+    controllers := make(map[string]map[output.UniverseKey][]EntityRange)
 
     if cfg == nil {
         return []string{}, controllers
     }
 
+    type slot struct {
+        entityID  int
+        dmxOffset int
+        format    pixel.Format
+    }
+    slots := make(map[string]map[output.UniverseKey][]slot)
     for _, e := range cfg.RoutingTable {
         ip := e.IP
-        if controllers[ip] == nil {
-            controllers[ip] = make(map[int][][2]int)
+        if slots[ip] == nil {
+            slots[ip] = make(map[output.UniverseKey][]slot)
         }
-        controllers[ip][e.Universe] = append(controllers[ip][e.Universe], [2]int{e.EntityID, e.EntityID})
+        slots[ip][e.Key()] = append(slots[ip][e.Key()], slot{entityID: e.EntityID, dmxOffset: e.DMXOffset, format: e.Format})
     }
 
-    if len(controllers) == 0 {
+    if len(slots) == 0 {
         return []string{}, controllers
     }
 
-    for _, uniMap := range controllers {
-        for u, ids := range uniMap {
-            if len(ids) == 0 {
-                continue
-            }
-
-            list := make([]int, len(ids))
-            for i, p := range ids {
-                list[i] = p[0]
-            }
-            sort.Ints(list)
-
-            var ranges [][2]int
+    for ip, uniMap := range slots {
+        controllers[ip] = make(map[output.UniverseKey][]EntityRange)
+        for u, list := range uniMap {
             if len(list) == 0 {
-                uniMap[u] = ranges
                 continue
             }
+            sort.Slice(list, func(i, j int) bool { return list[i].entityID < list[j].entityID })
 
-            start, end := list[0], list[0]
-            for _, id := range list[1:] {
-                if id == end+1 {
-                    end = id
+            var ranges []EntityRange
+            current := EntityRange{Start: list[0].entityID, End: list[0].entityID, DMXStart: list[0].dmxOffset + 1, Format: list[0].format}
+            lastOffset := list[0].dmxOffset
+            for _, s := range list[1:] {
+                if s.entityID == current.End+1 && s.format == current.Format && s.dmxOffset == lastOffset+current.Format.Channels() {
+                    current.End = s.entityID
                 } else {
-                    ranges = append(ranges, [2]int{start, end})
-                    start, end = id, id
+                    ranges = append(ranges, current)
+                    current = EntityRange{Start: s.entityID, End: s.entityID, DMXStart: s.dmxOffset + 1, Format: s.format}
                 }
+                lastOffset = s.dmxOffset
             }
-            ranges = append(ranges, [2]int{start, end})
-            uniMap[u] = ranges
+            ranges = append(ranges, current)
+            controllers[ip][u] = ranges
         }
     }
 
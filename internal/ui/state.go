@@ -3,6 +3,9 @@ package ui
 import (
     "fyne.io/fyne/v2"
     "guitarHetic/internal/config"
+    "guitarHetic/internal/domain/artnet"
+    "guitarHetic/internal/domain/output"
+    "guitarHetic/internal/domain/pixel"
     "sync"
 )
 
@@ -14,27 +17,84 @@ const (
     UniverseView ViewName = "universe_view"
 )
 
+type ledSlot struct {
+    Offset int
+    Format pixel.Format
+}
+
 type UIState struct {
-    allControllers      map[string]map[int][][2]int
+    allControllers      map[string]map[output.UniverseKey][]EntityRange
+    universeTargets     map[output.UniverseKey][]output.Target
     CurrentView         ViewName
     controllerIPs       []string
     selectedIP          string
     selectedDetails     []UniRange
-    selectedUniverse    int
+    selectedUniverse    output.UniverseKey
+    universeSlots       []ledSlot
     viewStack           []ViewName
     ledStateMutex       sync.RWMutex
     ledInputWidgets     []*LedWidget
     ledOutputWidgets    []*LedWidget
     universeViewContent fyne.CanvasObject
     lastOpenedFolder    fyne.ListableURI
+    discoveryDone       bool
+    discoveredNodes     []artnet.NodeInfo
 }
 
 func NewUIState(cfg *config.Config) *UIState {
     ips, ctrlMap := BuildModel(cfg)
+    var targets map[output.UniverseKey][]output.Target
+    if cfg != nil {
+        targets = cfg.UniverseTargets
+    }
     return &UIState{
-        allControllers: ctrlMap,
-        controllerIPs:  ips,
-        CurrentView:    IPListView,
-        viewStack:      make([]ViewName, 0),
+        allControllers:  ctrlMap,
+        universeTargets: targets,
+        controllerIPs:   ips,
+        CurrentView:     IPListView,
+        viewStack:       make([]ViewName, 0),
+    }
+}
+
+func (s *UIState) answeredIPs() map[string]bool {
+    answered := make(map[string]bool)
+    for _, node := range s.discoveredNodes {
+        answered[node.IP.String()] = true
+    }
+    return answered
+}
+
+// mirrorsOf renvoie les destinations de l'univers autres que le contrôleur ip.
+func (s *UIState) mirrorsOf(universe output.UniverseKey, ip string) []output.Target {
+    var mirrors []output.Target
+    for _, target := range s.universeTargets[universe] {
+        if target.Address != ip {
+            mirrors = append(mirrors, target)
+        }
+    }
+    return mirrors
+}
+
+// destinationsOf renvoie le contrôleur ip suivi des copies de l'univers.
+func (s *UIState) destinationsOf(universe output.UniverseKey, ip string) []output.Target {
+    controller := output.Target{Address: ip}
+    for _, target := range s.universeTargets[universe] {
+        if target.Address == ip {
+            controller = target
+        }
+    }
+    return append([]output.Target{controller}, s.mirrorsOf(universe, ip)...)
+}
+
+// isBroadcast indique si ip est déclarée comme adresse de diffusion : aucun
+// nœud n'y répond à l'ArtPoll.
+func (s *UIState) isBroadcast(ip string) bool {
+    for _, targets := range s.universeTargets {
+        for _, target := range targets {
+            if target.Address == ip && target.Broadcast {
+                return true
+            }
+        }
     }
+    return false
 }
@@ -1,16 +1,20 @@
 package ui
 
 import (
-    "guitarHetic/internal/domain/ehub"
+    "guitarHetic/internal/config"
+    "guitarHetic/internal/domain/artnet"
     "image/color"
 )
 
-type UniverseMonitorData struct {
-    UniverseID int
-    InputState []ehub.EHubEntityState
-    OutputDMX  [512]byte
+type NodeDiscoverer func() ([]artnet.NodeInfo, error)
+
+type NetworkInterface struct {
+    Name      string
+    Addresses []string
 }
 
+type InterfaceLister func() ([]NetworkInterface, error)
+
 type LedState struct {
     InputColors  []color.Color
     OutputColors []color.Color
@@ -23,4 +27,6 @@ type ConfigUpdateRequest struct {
     PatchFilePath     string
     ClearPatch        bool
     SetPatchingActive *bool
+    Network           *config.NetworkSettings
+    EHub              *config.EHubSettings
 }
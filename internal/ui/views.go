@@ -9,7 +9,11 @@ import (
     "fyne.io/fyne/v2/layout"
     "fyne.io/fyne/v2/theme"
     "fyne.io/fyne/v2/widget"
+    "guitarHetic/internal/config"
+    "guitarHetic/internal/domain/artnet"
+    "guitarHetic/internal/domain/output"
     "image/color"
+    "strconv"
     "strings"
 )
 
@@ -115,13 +119,31 @@ func buildHeader(state *UIState, controller *UIController) fyne.CanvasObject {
 }
 
 func buildIPListView(state *UIState, controller *UIController) fyne.CanvasObject {
+    answered := state.answeredIPs()
     list := widget.NewList(
         func() int { return len(state.controllerIPs) },
         func() fyne.CanvasObject {
-            return container.NewBorder(nil, nil, nil, widget.NewIcon(theme.NavigateNextIcon()), widget.NewLabel("Template IP"))
+            return container.NewBorder(nil, nil, widget.NewIcon(nil), widget.NewIcon(theme.NavigateNextIcon()), widget.NewLabel("Template IP"))
         },
         func(i widget.ListItemID, o fyne.CanvasObject) {
-            o.(*fyne.Container).Objects[0].(*widget.Label).SetText(state.controllerIPs[i])
+            ip := state.controllerIPs[i]
+            row := o.(*fyne.Container)
+            label := row.Objects[0].(*widget.Label)
+            status := row.Objects[1].(*widget.Icon)
+            switch {
+            case !state.discoveryDone:
+                label.SetText(ip)
+                status.SetResource(nil)
+            case state.isBroadcast(ip):
+                label.SetText(ip + "  (diffusion)")
+                status.SetResource(nil)
+            case answered[ip]:
+                label.SetText(ip + "  (a répondu à l'ArtPoll)")
+                status.SetResource(theme.ConfirmIcon())
+            default:
+                label.SetText(ip + "  (absent du réseau)")
+                status.SetResource(theme.WarningIcon())
+            }
         },
     )
     list.OnSelected = func(id widget.ListItemID) {
@@ -148,9 +170,13 @@ func buildDetailView(state *UIState, controller *UIController) fyne.CanvasObject
 
         parts := make([]string, len(currentDetail.Ranges))
         for i, rg := range currentDetail.Ranges {
-            parts[i] = fmt.Sprintf("%d à %d", rg[0], rg[1])
+            parts[i] = fmt.Sprintf("%d à %d, canal %d, %s", rg.Start, rg.End, rg.DMXStart, rg.Format)
         }
-        labelRanges := widget.NewLabel(fmt.Sprintf("Univers %d (%s)", currentDetail.Universe, strings.Join(parts, ", ")))
+        labelText := fmt.Sprintf("Univers %s : %s", formatUniverse(currentDetail.Universe), strings.Join(parts, " ; "))
+        if len(currentDetail.Mirrors) > 0 {
+            labelText += fmt.Sprintf("\nCopie vers : %s", config.FormatTargets(currentDetail.Mirrors))
+        }
+        labelRanges := widget.NewLabel(labelText)
 
         monitorButton := widget.NewButton("Monitorer", func() {
             controller.SelectUniverseAndShowDetails(currentDetail.Universe)
@@ -160,12 +186,12 @@ func buildDetailView(state *UIState, controller *UIController) fyne.CanvasObject
         ipInput := NewSizedEntry(200.0)
 
         dialogContent := container.NewVBox(
-            widget.NewLabel(fmt.Sprintf("Entrez la nouvelle IP pour l'univers %d", currentDetail.Universe)),
+            widget.NewLabel(fmt.Sprintf("Entrez les destinations de l'univers %s, séparées par des virgules :\nle contrôleur d'abord, puis les copies (préfixe broadcast: pour une adresse de diffusion)", formatUniverse(currentDetail.Universe))),
             ipInput,
         )
 
         ipDialog = dialog.NewCustomConfirm(
-            "Modifier l'IP de l'univers",
+            "Modifier les destinations de l'univers",
             "Sauvegarder",
             "Annuler",
             dialogContent,
@@ -179,7 +205,7 @@ func buildDetailView(state *UIState, controller *UIController) fyne.CanvasObject
         )
 
         editIPButton := widget.NewButtonWithIcon("", theme.SettingsIcon(), func() {
-            ipInput.SetText(controller.state.selectedIP)
+            ipInput.SetText(config.FormatTargets(controller.state.destinationsOf(currentDetail.Universe, controller.state.selectedIP)))
             ipDialog.Show()
         })
 
@@ -199,3 +225,116 @@ func buildDetailView(state *UIState, controller *UIController) fyne.CanvasObject
         container.NewPadded(universeList),
     ))
 }
+
+func buildLoadReportContent(report *config.Report) fyne.CanvasObject {
+    summary := widget.NewLabelWithStyle(report.Summary(), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+    issues := widget.NewList(
+        func() int { return len(report.Issues) },
+        func() fyne.CanvasObject {
+            return container.NewHBox(widget.NewIcon(theme.WarningIcon()), widget.NewLabel("Template"))
+        },
+        func(i widget.ListItemID, o fyne.CanvasObject) {
+            issue := report.Issues[i]
+            row := o.(*fyne.Container)
+            icon := theme.WarningIcon()
+            if issue.Severity == config.SeverityError {
+                icon = theme.ErrorIcon()
+            }
+            row.Objects[0].(*widget.Icon).SetResource(icon)
+            row.Objects[1].(*widget.Label).SetText(issue.String())
+        },
+    )
+    return container.NewBorder(container.NewVBox(summary, widget.NewSeparator()), nil, nil, nil, issues)
+}
+
+func buildNodeListContent(state *UIState) fyne.CanvasObject {
+    configured := make(map[string]bool)
+    for _, ip := range state.controllerIPs {
+        configured[ip] = true
+    }
+    answered := state.answeredIPs()
+
+    var missing []string
+    for _, ip := range state.controllerIPs {
+        if !answered[ip] && !state.isBroadcast(ip) {
+            missing = append(missing, ip)
+        }
+    }
+
+    items := []fyne.CanvasObject{
+        widget.NewLabelWithStyle(fmt.Sprintf("%d nœud(s) ont répondu", len(state.discoveredNodes)), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+    }
+    for _, node := range state.discoveredNodes {
+        ports := make([]string, 0)
+        for _, address := range node.OutputPortAddresses() {
+            ports = append(ports, formatUniverse(output.UniverseKey{Protocol: artnet.Protocol, Universe: int(address)}))
+        }
+        status := ""
+        if !configured[node.IP.String()] {
+            status = " [non configuré]"
+        }
+        items = append(items, widget.NewLabel(fmt.Sprintf("%s%s — %s / %s — sorties: %s — firmware %s",
+            node.IP, status, node.ShortName, node.LongName, strings.Join(ports, ", "), node.FirmwareString())))
+    }
+    if len(missing) > 0 {
+        items = append(items, widget.NewSeparator(),
+            widget.NewLabelWithStyle("Contrôleurs configurés sans réponse", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+            widget.NewLabel(strings.Join(missing, ", ")))
+    }
+
+    scroll := container.NewVScroll(container.NewVBox(items...))
+    scroll.SetMinSize(fyne.NewSize(800, 400))
+    return scroll
+}
+
+const automaticInterface = "Automatique (choix du système)"
+
+// buildNetworkContent liste les interfaces actives et renvoie le choix courant
+// de l'utilisateur pour la sortie et pour l'écoute eHub.
+func buildNetworkContent(interfaces []NetworkInterface, current config.NetworkSettings, eHub config.EHubSettings) (fyne.CanvasObject, func() (config.NetworkSettings, config.EHubSettings, error)) {
+    options := []string{automaticInterface}
+    values := map[string]string{automaticInterface: ""}
+    rows := []fyne.CanvasObject{
+        widget.NewLabelWithStyle("Interfaces disponibles", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+    }
+    owners := make(map[string]string)
+    for _, iface := range interfaces {
+        option := fmt.Sprintf("%s (%s)", iface.Name, strings.Join(iface.Addresses, ", "))
+        rows = append(rows, widget.NewLabel(option))
+        options = append(options, option)
+        values[option] = iface.Name
+        owners[iface.Name] = option
+        for _, address := range iface.Addresses {
+            owners[address] = option
+        }
+    }
+
+    // Un réglage saisi à la main peut désigner l'interface par son adresse.
+    newSelect := func(value string) *widget.Select {
+        sel := widget.NewSelect(options, nil)
+        sel.SetSelected(automaticInterface)
+        if option, ok := owners[value]; ok {
+            sel.SetSelected(option)
+        }
+        return sel
+    }
+    outputSelect := newSelect(current.Output)
+    eHubSelect := newSelect(eHub.Address)
+    portInput := NewSizedEntry(100.0)
+    portInput.SetText(strconv.Itoa(eHub.Port))
+
+    rows = append(rows, widget.NewSeparator(),
+        container.NewGridWithColumns(2, widget.NewLabel("Sortie Art-Net / sACN :"), outputSelect),
+        container.NewGridWithColumns(2, widget.NewLabel("Écoute eHub :"), eHubSelect),
+        container.NewGridWithColumns(2, widget.NewLabel("Port eHub :"), portInput),
+    )
+    selection := func() (config.NetworkSettings, config.EHubSettings, error) {
+        network := config.NetworkSettings{Output: values[outputSelect.Selected]}
+        port, err := strconv.Atoi(strings.TrimSpace(portInput.Text))
+        if err != nil || port <= 0 || port > 65535 {
+            return network, eHub, fmt.Errorf("port eHub invalide: '%s'", portInput.Text)
+        }
+        return network, config.EHubSettings{Address: values[eHubSelect.Selected], Port: port}, nil
+    }
+    return container.NewVScroll(container.NewVBox(rows...)), selection
+}
@@ -2,22 +2,42 @@ package main
 
 import (
     "context"
+    "flag"
+    "fmt"
     "fyne.io/fyne/v2/app"
-    app_ehub "guitarHetic/internal/application/ehub"
+    app_monitor "guitarHetic/internal/application/monitor"
     app_processor "guitarHetic/internal/application/processor"
     "guitarHetic/internal/config"
     domain_artnet "guitarHetic/internal/domain/artnet"
     "guitarHetic/internal/domain/ehub"
+    "guitarHetic/internal/domain/output"
     infra_artnet "guitarHetic/internal/infrastructure/artnet"
     infra_ehub "guitarHetic/internal/infrastructure/ehub"
+    "guitarHetic/internal/infrastructure/netif"
+    _ "guitarHetic/internal/infrastructure/sacn" // enregistre le transport sACN
+    "guitarHetic/internal/pipeline"
     "guitarHetic/internal/simulator"
     "guitarHetic/internal/ui"
     "log"
     "strconv"
     "strings"
+    "time"
 )
 
+const discoveryTimeout = 3 * time.Second
+
+// Le routeur sans interface graphique est construit séparément par cmd/router,
+// pour ne pas dépendre de Fyne ni d'un serveur d'affichage.
 func main() {
+    var opts pipeline.CommandLine
+    opts.RegisterFlags(flag.CommandLine)
+    flag.Parse()
+    opts.LoadSettings()
+
+    runGUI(opts)
+}
+
+func runGUI(opts pipeline.CommandLine) {
     log.Println("Démarrage du système...")
 
     ctx, cancel := context.WithCancel(context.Background())
@@ -28,16 +48,28 @@ func main() {
     eHubUpdateChannel := make(chan *ehub.EHubUpdateMsg, 1000)
     fakerUpdateChannel := make(chan *ehub.EHubUpdateMsg, 1000)
     fakerConfigOut := make(chan *ehub.EHubConfigMsg, 50)
-    monitorChan := make(chan *ui.UniverseMonitorData, 100)
+    monitorHub := app_monitor.NewHub()
+    rawPacketChannel := make(chan ehub.RawPacket, 1000)
+    eHubEndpoint := infra_ehub.NewEndpoint(rawPacketChannel)
+    defer eHubEndpoint.Close()
 
     var faker *simulator.Faker = nil
 
     a := app.New()
     a.Settings().SetTheme(&ui.ArtHeticTheme{})
     w := a.NewWindow("Guitare Hetic - Inspecteur ArtNet")
-    uiController := ui.NewUIController(a, faker, monitorChan, func(req ui.ConfigUpdateRequest) {
+    uiController := ui.NewUIController(a, faker, monitorHub.Subscribe(100), func(req ui.ConfigUpdateRequest) {
         configRequestChannel <- req
     })
+    artnetNode := infra_artnet.NewNode()
+    defer artnetNode.Close()
+    if err := artnetNode.Apply(ctx, opts.Settings); err != nil {
+        log.Printf("ERREUR: Le routeur ne répondra pas aux ArtPoll: %v", err)
+    }
+    uiController.SetNodeDiscoverer(func() ([]domain_artnet.NodeInfo, error) {
+        return artnetNode.Discover(ctx, opts.Settings, discoveryTimeout)
+    })
+    uiController.SetInterfaceLister(listNetworkInterfaces, opts.Settings.Network, opts.Settings.EHub)
     ui.RunUI(uiController, w)
 
     go func() {
@@ -79,49 +111,58 @@ func main() {
 
                 stopPipeline()
 
+                if req.Network != nil || req.EHub != nil {
+                    if req.Network != nil {
+                        log.Printf("Gestionnaire de Config: Interface de sortie '%s'", req.Network.Output)
+                        opts.Settings.Network = *req.Network
+                    }
+                    if req.EHub != nil {
+                        log.Printf("Gestionnaire de Config: Écoute eHub '%s' port %d", req.EHub.Address, req.EHub.Port)
+                        opts.Settings.EHub = *req.EHub
+                        // Le port choisi dans l'interface remplace celui de -port.
+                        opts.EHubPort = 0
+                    }
+                    if err := config.SaveSettings(opts.SettingsPath, opts.PersistedSettings()); err != nil {
+                        log.Printf("ERREUR: Impossible d'enregistrer les réglages: %v", err)
+                    }
+                }
+
                 if req.FilePath != "" {
                     log.Printf("Gestionnaire de Config: Chargement du fichier %s", req.FilePath)
-                    newConfig, err := config.Load(req.FilePath)
+                    newConfig, report, err := config.Load(req.FilePath)
                     if err != nil {
                         log.Printf("ERREUR: Impossible de charger le fichier de configuration: %v", err)
                         currentConfig = nil
                     } else {
                         currentConfig = newConfig
                     }
+                    if report.HasIssues() {
+                        acknowledged := make(chan struct{})
+                        uiController.ShowLoadReport(report, func() { close(acknowledged) })
+                        <-acknowledged
+                    }
                 }
 
                 if req.IPChanges != nil && currentConfig != nil {
                     log.Printf("Gestionnaire de Config: Application des changements d'IP: %v", req.IPChanges)
                     for key, newIP := range req.IPChanges {
                         if strings.HasPrefix(key, "universe:") {
-                            universeIDStr := strings.TrimPrefix(key, "universe:")
-                            universeID, err := strconv.Atoi(universeIDStr)
+                            universe, err := parseUniverseKey(strings.TrimPrefix(key, "universe:"))
                             if err != nil {
                                 log.Printf("ERREUR: Clé d'univers invalide: %s", key)
                                 continue
                             }
-                            log.Printf("  -> Changement spécifique pour l'univers %d vers l'IP %s", universeID, newIP)
-                            if _, ok := currentConfig.UniverseIP[universeID]; ok {
-                                currentConfig.UniverseIP[universeID] = newIP
-                            }
-                            for i, entry := range currentConfig.RoutingTable {
-                                if entry.Universe == universeID {
-                                    currentConfig.RoutingTable[i].IP = newIP
-                                }
+                            targets, err := config.ParseTargets(newIP)
+                            if err != nil {
+                                log.Printf("ERREUR: Destinations invalides pour l'univers %s: %v", universe, err)
+                                continue
                             }
+                            log.Printf("  -> Changement spécifique pour l'univers %s vers %s", universe, config.FormatTargets(targets))
+                            currentConfig.SetUniverseTargets(universe, targets)
                         } else {
                             oldIP := key
                             log.Printf("  -> Changement global de l'IP %s vers %s", oldIP, newIP)
-                            for i, entry := range currentConfig.RoutingTable {
-                                if entry.IP == oldIP {
-                                    currentConfig.RoutingTable[i].IP = newIP
-                                }
-                            }
-                            for u, ip := range currentConfig.UniverseIP {
-                                if ip == oldIP {
-                                    currentConfig.UniverseIP[u] = newIP
-                                }
-                            }
+                            currentConfig.ReplaceIP(oldIP, newIP)
                         }
                     }
                 }
@@ -141,10 +182,30 @@ func main() {
 
                 uiController.UpdateWithNewConfig(currentConfig)
 
+                artnetNode.SetUniverses(pipeline.ConfiguredUniverses(currentConfig))
+                if err := artnetNode.Apply(ctx, opts.Settings); err != nil {
+                    log.Printf("ERREUR: Le routeur ne répondra pas aux ArtPoll: %v", err)
+                }
+
+                if err := eHubEndpoint.Apply(ctx, opts.Settings.EHub); err != nil {
+                    log.Printf("ERREUR: Impossible d'écouter eHub: %v", err)
+                    uiController.ShowError(err)
+                }
+
                 if currentConfig != nil {
                     pipelineCtx, cancelFunc := context.WithCancel(ctx)
                     cancelPipeline = cancelFunc
-                    processorService = startPipeline(pipelineCtx, currentConfig, monitorChan, eHubUpdateChannel, fakerUpdateChannel, fakerConfigOut, fakerModeSwitch)
+                    pipelineOpts := opts.Pipeline()
+                    pipelineOpts.Faker = faker
+                    pipelineOpts.OnInputLoss = func(lost bool, message string) {
+                        uiController.SetStatus(message, lost)
+                    }
+                    service, _, err := pipeline.Start(pipelineCtx, currentConfig, monitorHub, rawPacketChannel, eHubUpdateChannel, fakerUpdateChannel, fakerConfigOut, fakerModeSwitch, pipelineOpts)
+                    if err != nil {
+                        log.Printf("ERREUR: Impossible de démarrer le pipeline de traitement: %v", err)
+                        uiController.ShowError(err)
+                    }
+                    processorService = service
                 }
 
             case <-ctx.Done():
@@ -160,74 +221,35 @@ func main() {
     log.Println("Arrêt complet de l'application.")
 }
 
-func startPipeline(ctx context.Context, cfg *config.Config, monitorChan chan *ui.UniverseMonitorData, eHubUpdateOut, fakerUpdateOut chan *ehub.EHubUpdateMsg, fakerConfigOut chan *ehub.EHubConfigMsg, fakerModeSwitch chan bool) *app_processor.Service {
-    log.Println("Pipeline: Démarrage des services...")
-
-    rawPacketChannel := make(chan ehub.RawPacket, 1000)
-    eHubConfigOut := make(chan *ehub.EHubConfigMsg, 50)
-    artnetQueue := make(chan domain_artnet.LEDMessage, 10000)
-    finalConfigIn := make(chan *ehub.EHubConfigMsg, 50)
-    finalUpdateIn := make(chan *ehub.EHubUpdateMsg, 1000)
-
-    listener, err := infra_ehub.NewListener(8765, rawPacketChannel)
+func listNetworkInterfaces() ([]ui.NetworkInterface, error) {
+    interfaces, err := netif.List()
     if err != nil {
-        log.Printf("ERREUR CRITIQUE: Impossible de créer le listener eHub: %v", err)
-        return nil
+        return nil, err
+    }
+    result := make([]ui.NetworkInterface, 0, len(interfaces))
+    for _, iface := range interfaces {
+        addresses := make([]string, len(iface.Addresses))
+        for i, address := range iface.Addresses {
+            addresses[i] = address.String()
+        }
+        result = append(result, ui.NetworkInterface{Name: iface.Name, Addresses: addresses})
     }
-    parser := app_ehub.NewParser()
-    eHubService := app_ehub.NewService(rawPacketChannel, parser, eHubConfigOut, eHubUpdateOut)
-    processorService, physicalConfigOut := app_processor.NewService(finalConfigIn, finalUpdateIn, artnetQueue, monitorChan)
+    return result, nil
+}
 
-    sender, err := infra_artnet.NewSender(cfg.UniverseIP)
+// parseUniverseKey lit la clé "<protocole>:<univers>" envoyée par l'interface.
+func parseUniverseKey(s string) (output.UniverseKey, error) {
+    protocolStr, universeStr, ok := strings.Cut(s, ":")
+    if !ok {
+        return output.UniverseKey{}, fmt.Errorf("clé d'univers sans protocole: '%s'", s)
+    }
+    protocol, err := output.ParseProtocol(protocolStr)
     if err != nil {
-        log.Printf("ERREUR: Impossible d'initialiser le sender ArtNet: %v", err)
-        listener.Start(ctx)
-        return nil
+        return output.UniverseKey{}, err
     }
-
-    go func() {
-        isFakerActive := false
-        log.Println("Aiguilleur: Démarré en mode LIVE.")
-        for {
-            select {
-            case <-ctx.Done():
-                log.Println("Aiguilleur: Arrêt.")
-                return
-            case mode := <-fakerModeSwitch:
-                if mode != isFakerActive {
-                    isFakerActive = mode
-                    if isFakerActive {
-                        log.Println("Aiguilleur: Passage en mode FAKER.")
-                    } else {
-                        log.Println("Aiguilleur: Retour au mode LIVE.")
-                    }
-                }
-            case msg := <-fakerUpdateOut:
-                if isFakerActive {
-                    finalUpdateIn <- msg
-                }
-            case msg := <-eHubUpdateOut:
-                if !isFakerActive {
-                    finalUpdateIn <- msg
-                }
-            case msg := <-fakerConfigOut:
-                if isFakerActive {
-                    finalConfigIn <- msg
-                }
-            case msg := <-eHubConfigOut:
-                if !isFakerActive {
-                    finalConfigIn <- msg
-                }
-            }
-        }
-    }()
-
-    listener.Start(ctx)
-    eHubService.Start()
-    processorService.Start()
-    go sender.Run(ctx, artnetQueue)
-
-    physicalConfigOut <- cfg
-
-    return processorService
+    universe, err := strconv.Atoi(universeStr)
+    if err != nil {
+        return output.UniverseKey{}, err
+    }
+    return output.UniverseKey{Protocol: protocol, Universe: universe}, nil
 }